@@ -1,3 +0,0 @@
-// Generated by `wit-bindgen-wrpc-go` 0.11.0. DO NOT EDIT!
-// imports package contains wRPC bindings for `imports` world
-package imports
@@ -2,6 +2,7 @@
 package ticker
 
 import (
+	bytes "bytes"
 	context "context"
 	binary "encoding/binary"
 	errors "errors"
@@ -9,6 +10,8 @@ import (
 	io "io"
 	slog "log/slog"
 	math "math"
+	sync "sync"
+	atomic "sync/atomic"
 	utf8 "unicode/utf8"
 	wrpc "wrpc.io/go"
 )
@@ -123,21 +126,250 @@ func (v *TaskError) WriteToIndex(w wrpc.ByteWriter) (func(wrpc.IndexWriter) erro
 	}
 	return nil, nil
 }
-func Task(ctx__ context.Context, wrpc__ wrpc.Invoker) (r0__ *TaskError, err__ error) {
+
+// Details of the scheduled job which triggered the task.
+type InvocationContext struct {
+	// Unique ID of the job within the provider
+	JobId string
+	// Name of the link the job was created from
+	LinkName string
+	// Schedule type from the link config e.g. interval, cron, startup
+	ScheduleType string
+	// Time the job was scheduled to fire, in milliseconds since the unix epoch
+	ScheduledAt uint64
+	// Time the job actually fired, in milliseconds since the unix epoch
+	FiredAt uint64
+	// Number of times the job has fired, including this run
+	RunCount uint64
+	// Invocation attempt for this run, starting at 1
+	Attempt uint32
+}
+
+func (v *InvocationContext) String() string { return "InvocationContext" }
+
+func (v *InvocationContext) WriteToIndex(w wrpc.ByteWriter) (func(wrpc.IndexWriter) error, error) {
+	writes := make(map[uint32]func(wrpc.IndexWriter) error, 7)
+	slog.Debug("writing field", "name", "job-id")
+	write0, err := (func(wrpc.IndexWriter) error)(nil), func(v string, w io.Writer) (err error) {
+		n := len(v)
+		if n > math.MaxUint32 {
+			return fmt.Errorf("string byte length of %d overflows a 32-bit integer", n)
+		}
+		if err = func(v int, w io.Writer) error {
+			b := make([]byte, binary.MaxVarintLen32)
+			i := binary.PutUvarint(b, uint64(v))
+			slog.Debug("writing string byte length", "len", n)
+			_, err = w.Write(b[:i])
+			return err
+		}(n, w); err != nil {
+			return fmt.Errorf("failed to write string byte length of %d: %w", n, err)
+		}
+		slog.Debug("writing string bytes")
+		_, err = w.Write([]byte(v))
+		if err != nil {
+			return fmt.Errorf("failed to write string bytes: %w", err)
+		}
+		return nil
+	}(v.JobId, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `job-id` field: %w", err)
+	}
+	if write0 != nil {
+		writes[0] = write0
+	}
+	slog.Debug("writing field", "name", "link-name")
+	write1, err := (func(wrpc.IndexWriter) error)(nil), func(v string, w io.Writer) (err error) {
+		n := len(v)
+		if n > math.MaxUint32 {
+			return fmt.Errorf("string byte length of %d overflows a 32-bit integer", n)
+		}
+		if err = func(v int, w io.Writer) error {
+			b := make([]byte, binary.MaxVarintLen32)
+			i := binary.PutUvarint(b, uint64(v))
+			slog.Debug("writing string byte length", "len", n)
+			_, err = w.Write(b[:i])
+			return err
+		}(n, w); err != nil {
+			return fmt.Errorf("failed to write string byte length of %d: %w", n, err)
+		}
+		slog.Debug("writing string bytes")
+		_, err = w.Write([]byte(v))
+		if err != nil {
+			return fmt.Errorf("failed to write string bytes: %w", err)
+		}
+		return nil
+	}(v.LinkName, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `link-name` field: %w", err)
+	}
+	if write1 != nil {
+		writes[1] = write1
+	}
+	slog.Debug("writing field", "name", "schedule-type")
+	write2, err := (func(wrpc.IndexWriter) error)(nil), func(v string, w io.Writer) (err error) {
+		n := len(v)
+		if n > math.MaxUint32 {
+			return fmt.Errorf("string byte length of %d overflows a 32-bit integer", n)
+		}
+		if err = func(v int, w io.Writer) error {
+			b := make([]byte, binary.MaxVarintLen32)
+			i := binary.PutUvarint(b, uint64(v))
+			slog.Debug("writing string byte length", "len", n)
+			_, err = w.Write(b[:i])
+			return err
+		}(n, w); err != nil {
+			return fmt.Errorf("failed to write string byte length of %d: %w", n, err)
+		}
+		slog.Debug("writing string bytes")
+		_, err = w.Write([]byte(v))
+		if err != nil {
+			return fmt.Errorf("failed to write string bytes: %w", err)
+		}
+		return nil
+	}(v.ScheduleType, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `schedule-type` field: %w", err)
+	}
+	if write2 != nil {
+		writes[2] = write2
+	}
+	slog.Debug("writing field", "name", "scheduled-at")
+	write3, err := (func(wrpc.IndexWriter) error)(nil), func(v uint64, w interface {
+		io.ByteWriter
+		io.Writer
+	}) (err error) {
+		b := make([]byte, binary.MaxVarintLen64)
+		i := binary.PutUvarint(b, uint64(v))
+		slog.Debug("writing u64")
+		_, err = w.Write(b[:i])
+		return err
+	}(v.ScheduledAt, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `scheduled-at` field: %w", err)
+	}
+	if write3 != nil {
+		writes[3] = write3
+	}
+	slog.Debug("writing field", "name", "fired-at")
+	write4, err := (func(wrpc.IndexWriter) error)(nil), func(v uint64, w interface {
+		io.ByteWriter
+		io.Writer
+	}) (err error) {
+		b := make([]byte, binary.MaxVarintLen64)
+		i := binary.PutUvarint(b, uint64(v))
+		slog.Debug("writing u64")
+		_, err = w.Write(b[:i])
+		return err
+	}(v.FiredAt, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `fired-at` field: %w", err)
+	}
+	if write4 != nil {
+		writes[4] = write4
+	}
+	slog.Debug("writing field", "name", "run-count")
+	write5, err := (func(wrpc.IndexWriter) error)(nil), func(v uint64, w interface {
+		io.ByteWriter
+		io.Writer
+	}) (err error) {
+		b := make([]byte, binary.MaxVarintLen64)
+		i := binary.PutUvarint(b, uint64(v))
+		slog.Debug("writing u64")
+		_, err = w.Write(b[:i])
+		return err
+	}(v.RunCount, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `run-count` field: %w", err)
+	}
+	if write5 != nil {
+		writes[5] = write5
+	}
+	slog.Debug("writing field", "name", "attempt")
+	write6, err := (func(wrpc.IndexWriter) error)(nil), func(v uint32, w interface {
+		io.ByteWriter
+		io.Writer
+	}) (err error) {
+		b := make([]byte, binary.MaxVarintLen32)
+		i := binary.PutUvarint(b, uint64(v))
+		slog.Debug("writing u32")
+		_, err = w.Write(b[:i])
+		return err
+	}(v.Attempt, w)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write `attempt` field: %w", err)
+	}
+	if write6 != nil {
+		writes[6] = write6
+	}
+
+	if len(writes) > 0 {
+		return func(w wrpc.IndexWriter) error {
+			var wg sync.WaitGroup
+			var wgErr atomic.Value
+			for index, write := range writes {
+				wg.Add(1)
+				w, err := w.Index(index)
+				if err != nil {
+					return fmt.Errorf("failed to index nested record writer: %w", err)
+				}
+				write := write
+				go func() {
+					defer wg.Done()
+					if err := write(w); err != nil {
+						wgErr.Store(err)
+					}
+				}()
+			}
+			wg.Wait()
+			err := wgErr.Load()
+			if err == nil {
+				return nil
+			}
+			return err.(error)
+		}, nil
+	}
+	return nil, nil
+}
+func Task(ctx__ context.Context, wrpc__ wrpc.Invoker, invocation *InvocationContext) (r0__ *TaskError, err__ error) {
+	var buf__ bytes.Buffer
+	writes__ := make(map[uint32]func(wrpc.IndexWriter) error, 1)
+	write0__, err__ := (invocation).WriteToIndex(&buf__)
+	if err__ != nil {
+		err__ = fmt.Errorf("failed to write `invocation` parameter: %w", err__)
+		return
+	}
+	if write0__ != nil {
+		writes__[0] = write0__
+	}
 	var w__ wrpc.IndexWriteCloser
 	var r__ wrpc.IndexReadCloser
-	w__, r__, err__ = wrpc__.Invoke(ctx__, "jamesstocktonj1:ticker/ticker@0.1.0", "task", nil)
+	w__, r__, err__ = wrpc__.Invoke(ctx__, "jamesstocktonj1:ticker/ticker@0.2.0", "task", buf__.Bytes())
 	if err__ != nil {
 		err__ = fmt.Errorf("failed to invoke `task`: %w", err__)
 		return
 	}
 	defer func() {
 		if err := r__.Close(); err != nil {
-			slog.ErrorContext(ctx__, "failed to close reader", "instance", "jamesstocktonj1:ticker/ticker@0.1.0", "name", "task", "err", err)
+			slog.ErrorContext(ctx__, "failed to close reader", "instance", "jamesstocktonj1:ticker/ticker@0.2.0", "name", "task", "err", err)
 		}
 	}()
+	if len(writes__) > 0 {
+		for index, write := range writes__ {
+			w, err := w__.Index(index)
+			if err != nil {
+				err__ = fmt.Errorf("failed to index writer at index `%v`: %w", index, err)
+				return
+			}
+			write := write
+			go func() {
+				if err := write(w); err != nil {
+					slog.WarnContext(ctx__, "failed to write nested parameter", "instance", "jamesstocktonj1:ticker/ticker@0.2.0", "name", "task", "err", err)
+				}
+			}()
+		}
+	}
 	if cErr__ := w__.Close(); cErr__ != nil {
-		slog.DebugContext(ctx__, "failed to close outgoing stream", "instance", "jamesstocktonj1:ticker/ticker@0.1.0", "name", "task", "err", cErr__)
+		slog.DebugContext(ctx__, "failed to close outgoing stream", "instance", "jamesstocktonj1:ticker/ticker@0.2.0", "name", "task", "err", cErr__)
 	}
 	r0__, err__ = func(r wrpc.IndexReadCloser, path ...uint32) (*TaskError, error) {
 		v := &TaskError{}
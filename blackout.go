@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrInvalidBlackout = errors.New("invalid blackout window")
+
+	blackoutParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+)
+
+// BlackoutWindow is a period of time in which runs are skipped, either a
+// single range of time or one which recurs on a cron schedule.
+type BlackoutWindow struct {
+	spec     string
+	start    time.Time
+	end      time.Time
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// Contains reports whether the given time falls inside the window.
+func (w *BlackoutWindow) Contains(at time.Time) bool {
+	if w.schedule == nil {
+		return !at.Before(w.start) && at.Before(w.end)
+	}
+
+	// The window is open if it last started within its duration of the time
+	start := w.schedule.Next(at.Add(-w.duration))
+	return !start.IsZero() && !start.After(at)
+}
+
+func (w *BlackoutWindow) String() string {
+	return w.spec
+}
+
+// parseBlackouts parses a semicolon separated list of blackout windows. Each
+// window is either a cron spec and a duration, e.g. "0 2 * * SAT for 4h", or
+// an RFC3339 time range, e.g. "2026-11-01T00:00:00Z/2026-11-01T04:00:00Z".
+// Cron specs are evaluated in the given location unless they start with a
+// CRON_TZ= prefix.
+func parseBlackouts(value string, location *time.Location) ([]*BlackoutWindow, error) {
+	windows := []*BlackoutWindow{}
+	for _, spec := range strings.Split(value, ";") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		window, err := parseBlackout(spec, location)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseBlackout(spec string, location *time.Location) (*BlackoutWindow, error) {
+	if cronSpec, durationSpec, ok := strings.Cut(spec, " for "); ok {
+		duration, err := time.ParseDuration(strings.TrimSpace(durationSpec))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("%w: %s: duration %s", ErrInvalidBlackout, spec, durationSpec)
+		}
+
+		cronSpec = strings.TrimSpace(cronSpec)
+		if _, _, ok := splitCronTimezone(cronSpec); !ok && location != nil {
+			cronSpec = fmt.Sprintf("CRON_TZ=%s %s", location, cronSpec)
+		}
+		schedule, err := blackoutParser.Parse(cronSpec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBlackout, spec, err)
+		}
+
+		return &BlackoutWindow{
+			spec:     spec,
+			schedule: schedule,
+			duration: duration,
+		}, nil
+	}
+
+	startSpec, endSpec, ok := strings.Cut(spec, "/")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBlackout, spec)
+	}
+
+	start, err := time.Parse(time.RFC3339, strings.TrimSpace(startSpec))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: start %s", ErrInvalidBlackout, spec, startSpec)
+	}
+	end, err := time.Parse(time.RFC3339, strings.TrimSpace(endSpec))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: end %s", ErrInvalidBlackout, spec, endSpec)
+	} else if !end.After(start) {
+		return nil, fmt.Errorf("%w: %s: end is not after start", ErrInvalidBlackout, spec)
+	}
+
+	return &BlackoutWindow{
+		spec:  spec,
+		start: start,
+		end:   end,
+	}, nil
+}
+
+// getBlackout returns the first of the windows which contains the given time.
+func getBlackout(windows []*BlackoutWindow, at time.Time) (*BlackoutWindow, bool) {
+	for _, window := range windows {
+		if window.Contains(at) {
+			return window, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBlackouts(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		value    string
+		location *time.Location
+		inside   []time.Time
+		outside  []time.Time
+		err      string
+	}{
+		{
+			name:  "time range",
+			value: "2026-11-01T00:00:00Z/2026-11-01T04:00:00Z",
+			inside: []time.Time{
+				time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 3, 59, 59, 0, time.UTC),
+			},
+			outside: []time.Time{
+				time.Date(2026, 10, 31, 23, 59, 59, 0, time.UTC),
+				time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "cron window",
+			value: "0 2 * * SAT for 4h",
+			inside: []time.Time{
+				time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 17, 5, 59, 0, 0, time.UTC),
+			},
+			outside: []time.Time{
+				time.Date(2026, 10, 17, 1, 59, 0, 0, time.UTC),
+				time.Date(2026, 10, 17, 6, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "cron window in location",
+			value:    "0 2 * * * for 1h",
+			location: newYork,
+			inside: []time.Time{
+				time.Date(2026, 7, 1, 6, 30, 0, 0, time.UTC),
+			},
+			outside: []time.Time{
+				time.Date(2026, 7, 1, 2, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "cron window with time zone prefix",
+			value:    "CRON_TZ=UTC 0 2 * * * for 1h",
+			location: newYork,
+			inside: []time.Time{
+				time.Date(2026, 7, 1, 2, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "multiple windows",
+			value: "0 2 * * SAT for 4h; 2026-12-25T00:00:00Z/2026-12-26T00:00:00Z",
+			inside: []time.Time{
+				time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC),
+			},
+			outside: []time.Time{
+				time.Date(2026, 12, 24, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "invalid duration",
+			value: "0 2 * * SAT for ever",
+			err:   "duration ever",
+		},
+		{
+			name:  "invalid cron",
+			value: "0 2 * * FUNDAY for 1h",
+			err:   "0 2 * * FUNDAY for 1h",
+		},
+		{
+			name:  "invalid range",
+			value: "2026-11-01/2026-11-02",
+			err:   "start 2026-11-01",
+		},
+		{
+			name:  "end before start",
+			value: "2026-11-02T00:00:00Z/2026-11-01T00:00:00Z",
+			err:   "end is not after start",
+		},
+		{
+			name:  "unknown format",
+			value: "weekends",
+			err:   "weekends",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			windows, err := parseBlackouts(test.value, test.location)
+			if test.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidBlackout)
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			for _, at := range test.inside {
+				_, ok := getBlackout(windows, at)
+				assert.True(t, ok, "expected %s inside blackout", at)
+			}
+			for _, at := range test.outside {
+				_, ok := getBlackout(windows, at)
+				assert.False(t, ok, "expected %s outside blackout", at)
+			}
+		})
+	}
+}
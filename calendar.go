@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// maxShiftDays is the furthest a run is moved to reach a business day.
+	maxShiftDays = 31
+	// maxSearchYears bounds the search for the next run of a schedule which
+	// may never fall on a business day.
+	maxSearchYears = 2
+)
+
+var (
+	ErrInvalidCalendar = errors.New("invalid holiday calendar")
+)
+
+// BusinessCalendar decides which days are business days, from the working
+// days of the week and a list of holidays, and moves the runs of a schedule
+// which fall on other days.
+type BusinessCalendar struct {
+	holidays map[string]bool
+	workdays map[time.Weekday]bool
+	mode     string
+}
+
+func NewBusinessCalendar(holidays []string, workdays []time.Weekday, mode string) *BusinessCalendar {
+	c := &BusinessCalendar{
+		holidays: make(map[string]bool),
+		workdays: make(map[time.Weekday]bool),
+		mode:     mode,
+	}
+	for _, holiday := range holidays {
+		c.holidays[holiday] = true
+	}
+	for _, workday := range workdays {
+		c.workdays[workday] = true
+	}
+	return c
+}
+
+// IsBusinessDay reports whether the date of the given time, in its own
+// location, is a business day.
+func (c *BusinessCalendar) IsBusinessDay(at time.Time) bool {
+	return c.workdays[at.Weekday()] && !c.holidays[at.Format(time.DateOnly)]
+}
+
+// shift moves a time by whole days in the given direction until it reaches a
+// business day, keeping its time of day.
+func (c *BusinessCalendar) shift(at time.Time, step int) (time.Time, bool) {
+	for i := 0; i <= maxShiftDays; i++ {
+		day := at.AddDate(0, 0, i*step)
+		if c.IsBusinessDay(day) {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Adjust wraps the next function of a schedule so its runs only fall on
+// business days, either skipping the other runs or moving them to the next
+// or previous business day.
+func (c *BusinessCalendar) Adjust(next func(time.Time) time.Time) func(time.Time) time.Time {
+	switch c.mode {
+	case calendarModeNext:
+		return c.adjustNext(next)
+	case calendarModePrevious:
+		return c.adjustPrevious(next)
+	default:
+		return c.adjustSkip(next)
+	}
+}
+
+func (c *BusinessCalendar) adjustSkip(next func(time.Time) time.Time) func(time.Time) time.Time {
+	return func(from time.Time) time.Time {
+		limit := from.AddDate(maxSearchYears, 0, 0)
+		for run := next(from); !run.IsZero() && run.Before(limit); run = next(run) {
+			if c.IsBusinessDay(run) {
+				return run
+			}
+		}
+		return time.Time{}
+	}
+}
+
+func (c *BusinessCalendar) adjustNext(next func(time.Time) time.Time) func(time.Time) time.Time {
+	return func(from time.Time) time.Time {
+		// Runs on the days off just before may be moved to after the time
+		start := from
+		day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+		for i := 1; i <= maxShiftDays; i++ {
+			previous := day.AddDate(0, 0, -i)
+			if c.IsBusinessDay(previous) {
+				break
+			}
+			start = previous.Add(-time.Nanosecond)
+		}
+
+		var best time.Time
+		limit := from.AddDate(maxSearchYears, 0, 0)
+		for run := next(start); !run.IsZero() && run.Before(limit); run = next(run) {
+			// Runs are only ever moved later, so later runs cannot be sooner
+			if !best.IsZero() && run.After(best) {
+				break
+			}
+
+			shifted, ok := c.shift(run, 1)
+			if ok && shifted.After(from) && (best.IsZero() || shifted.Before(best)) {
+				best = shifted
+			}
+		}
+		return best
+	}
+}
+
+func (c *BusinessCalendar) adjustPrevious(next func(time.Time) time.Time) func(time.Time) time.Time {
+	return func(from time.Time) time.Time {
+		var best time.Time
+		limit := from.AddDate(maxSearchYears, 0, 0)
+		for run := next(from); !run.IsZero() && run.Before(limit); run = next(run) {
+			// Runs after a business day following the best run can only be
+			// moved back as far as that day
+			if !best.IsZero() && c.IsBusinessDay(run) && run.Format(time.DateOnly) > best.Format(time.DateOnly) {
+				break
+			}
+
+			shifted, ok := c.shift(run, -1)
+			if ok && shifted.After(from) && (best.IsZero() || shifted.Before(best)) {
+				best = shifted
+			}
+		}
+		return best
+	}
+}
+
+// loadHolidays reads a list of holiday dates, either from an iCalendar file
+// if the value is a path ending in .ics, or as comma separated dates, e.g.
+// "2026-12-25,2026-12-26".
+func loadHolidays(value string) ([]string, error) {
+	if strings.HasSuffix(strings.ToLower(value), ".ics") {
+		file, err := os.Open(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+		}
+		defer file.Close()
+
+		return parseICS(file)
+	}
+
+	holidays := []string{}
+	for _, date := range strings.Split(value, ",") {
+		date = strings.TrimSpace(date)
+
+		holiday, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date %s", ErrInvalidCalendar, date)
+		}
+		holidays = append(holidays, holiday.Format(time.DateOnly))
+	}
+	return holidays, nil
+}
+
+// parseICS returns the dates covered by the events of an iCalendar file.
+// Events last from their start date up to, but not including, their end
+// date. Recurrence rules are not expanded.
+func parseICS(r io.Reader) ([]string, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+
+	holidays := []string{}
+	var start, end time.Time
+	inEvent := false
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent = true
+				start, end = time.Time{}, time.Time{}
+			}
+		case "DTSTART":
+			if inEvent {
+				start, err = parseICSDate(value)
+			}
+		case "DTEND":
+			if inEvent {
+				end, err = parseICSDate(value)
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+
+			if start.IsZero() {
+				return nil, fmt.Errorf("%w: event without DTSTART", ErrInvalidCalendar)
+			}
+			holidays = append(holidays, start.Format(time.DateOnly))
+			for day := start.AddDate(0, 0, 1); day.Before(end); day = day.AddDate(0, 0, 1) {
+				holidays = append(holidays, day.Format(time.DateOnly))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCalendar, line, err)
+		}
+	}
+	return holidays, nil
+}
+
+// unfoldICS joins iCalendar content lines which are folded over several
+// lines, each continuation starting with a space or tab.
+func unfoldICS(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICSDate parses the date of an iCalendar DATE or DATE-TIME value,
+// e.g. "20261225" or "20261225T000000Z".
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %s", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
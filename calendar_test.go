@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessCalendar(t *testing.T) {
+	calendar := NewBusinessCalendar([]string{"2026-12-25"}, []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, calendarModeSkip)
+
+	assert.True(t, calendar.IsBusinessDay(time.Date(2026, 12, 24, 9, 0, 0, 0, time.UTC)))
+	assert.False(t, calendar.IsBusinessDay(time.Date(2026, 12, 25, 9, 0, 0, 0, time.UTC)))
+	assert.False(t, calendar.IsBusinessDay(time.Date(2026, 12, 26, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, calendar.IsBusinessDay(time.Date(2026, 12, 28, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestBusinessCalendarAdjust(t *testing.T) {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	from := time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	// The 1st of August 2026 is a Saturday and the 1st of September a holiday
+	next, err := newBaseScheduleFunc(map[string]string{
+		"type":          "monthly",
+		"days_of_month": "1",
+		"at":            "09:00",
+		"timezone":      "UTC",
+	}, from)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected []time.Time
+	}{
+		{
+			name: "skip",
+			mode: calendarModeSkip,
+			expected: []time.Time{
+				time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "next business day",
+			mode: calendarModeNext,
+			expected: []time.Time{
+				time.Date(2026, 8, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 9, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "previous business day",
+			mode: calendarModePrevious,
+			expected: []time.Time{
+				time.Date(2026, 7, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 8, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calendar := NewBusinessCalendar([]string{"2026-09-01", "2026-11-02"}, weekdays, test.mode)
+			adjusted := calendar.Adjust(next)
+
+			run := from
+			for _, expected := range test.expected {
+				run = adjusted(run)
+				assert.Equal(t, expected, run)
+			}
+		})
+	}
+
+	t.Run("never a business day", func(t *testing.T) {
+		calendar := NewBusinessCalendar(nil, []time.Weekday{time.Sunday}, calendarModeSkip)
+		weekly, err := newBaseScheduleFunc(map[string]string{
+			"type": "weekly",
+			"days": "mon",
+			"at":   "09:00",
+		}, from)
+		assert.NoError(t, err)
+		assert.True(t, calendar.Adjust(weekly)(from).IsZero())
+	})
+}
+
+func TestLoadHolidays(t *testing.T) {
+	holidays, err := loadHolidays("2026-12-25, 2026-12-26")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2026-12-25", "2026-12-26"}, holidays)
+
+	_, err = loadHolidays("2026-12-25,christmas")
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+	assert.ErrorContains(t, err, "christmas")
+
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	err = os.WriteFile(path, []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261225\r\nSUMMARY:Christmas Day\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"), 0o600)
+	assert.NoError(t, err)
+	holidays, err = loadHolidays(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2026-12-25"}, holidays)
+
+	_, err = loadHolidays(filepath.Join(t.TempDir(), "missing.ics"))
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+}
+
+func TestParseICS(t *testing.T) {
+	tests := []struct {
+		name     string
+		ics      string
+		expected []string
+		err      string
+	}{
+		{
+			name: "all day events",
+			ics: `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:1
+DTSTART;VALUE=DATE:20261225
+DTEND;VALUE=DATE:20261227
+SUMMARY:Christmas
+END:VEVENT
+BEGIN:VEVENT
+UID:2
+DTSTART;VALUE=DATE:20270101
+SUMMARY:New Year's Day
+END:VEVENT
+END:VCALENDAR`,
+			expected: []string{"2026-12-25", "2026-12-26", "2027-01-01"},
+		},
+		{
+			name: "date times and folded lines",
+			ics: `BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:A holiday with a long
+ name
+DTSTART:20260831T000000Z
+END:VEVENT
+END:VCALENDAR`,
+			expected: []string{"2026-08-31"},
+		},
+		{
+			name:     "no events",
+			ics:      "BEGIN:VCALENDAR\nEND:VCALENDAR",
+			expected: []string{},
+		},
+		{
+			name: "event without start",
+			ics: `BEGIN:VEVENT
+SUMMARY:Nothing
+END:VEVENT`,
+			err: "event without DTSTART",
+		},
+		{
+			name: "invalid date",
+			ics: `BEGIN:VEVENT
+DTSTART:2026
+END:VEVENT`,
+			err: "DTSTART:2026",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			holidays, err := parseICS(strings.NewReader(test.ics))
+			if test.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidCalendar)
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, holidays)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// Control subjects are scoped to a provider on a lattice, e.g.
+	// wasmbus.ticker.default.<provider_id>.jobs.list, and to a replica of it
+	// by the host it runs on, e.g. wasmbus.ticker.default.<provider_id>.<host_id>.history
+	controlSubjectPrefix = "wasmbus.ticker"
+
+	// controlQueue picks a single replica to answer requests which act on
+	// one replica, such as triggering a job.
+	controlQueue = "ticker-provider"
+
+	controlHistory     = "history"
+	controlJobsList    = "jobs.list"
+	controlJobsTrigger = "jobs.trigger"
+	controlJobsPause   = "jobs.pause"
+	controlJobsResume  = "jobs.resume"
+)
+
+var (
+	ErrInvalidRequest   = errors.New("invalid control request")
+	ErrTaskNotScheduled = errors.New("error ticker task not scheduled")
+	ErrTaskPaused       = errors.New("error ticker task paused")
+	ErrSharedJob        = errors.New("error ticker task shared by replicas")
+)
+
+// ControlResponse is the JSON reply to a request on a control subject,
+// holding either the result of the request or the error it failed with, and
+// the host of the replica which answered.
+type ControlResponse struct {
+	Host    string      `json:"host"`
+	Records []RunRecord `json:"records,omitempty"`
+	Jobs    []JobStatus `json:"jobs,omitempty"`
+	Job     *JobStatus  `json:"job,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// JobRequest is the body of a request on a jobs subject. Jobs are listed by
+// link and component, and are otherwise picked by their key or ID.
+type JobRequest struct {
+	Key       string `json:"key,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Link      string `json:"link,omitempty"`
+	Component string `json:"component,omitempty"`
+}
+
+// JobStatus describes a job of the provider, either from a link schedule or
+// scheduled by a component at runtime.
+type JobStatus struct {
+	Key         string     `json:"key"`
+	ID          string     `json:"id,omitempty"`
+	Component   string     `json:"component"`
+	Link        string     `json:"link,omitempty"`
+	Schedule    string     `json:"schedule,omitempty"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	Runtime     bool       `json:"runtime,omitempty"`
+	Paused      bool       `json:"paused"`
+	NextRun     *time.Time `json:"next_run,omitempty"`
+	State       TaskState  `json:"state"`
+}
+
+// getJobStatus describes a task, with its next run from the scheduler
+// unless the task is paused or has nothing left to run.
+func getJobStatus(task *TickerTask) JobStatus {
+	state := task.State()
+	status := JobStatus{
+		Key:         task.key,
+		Component:   task.Component,
+		Link:        task.Link,
+		Schedule:    task.Schedule,
+		Type:        task.Type,
+		Description: getScheduleDescription(task.Config),
+		Runtime:     task.runtime,
+		Paused:      state.Paused,
+		State:       state,
+	}
+	if task.ID != uuid.Nil {
+		status.ID = task.ID.String()
+	}
+	if nextRun, ok := task.NextRun(); ok && !state.Paused {
+		status.NextRun = &nextRun
+	}
+	return status
+}
+
+func getControlSubject(lattice, providerID, operation string) string {
+	return fmt.Sprintf("%s.%s.%s.%s", controlSubjectPrefix, lattice, providerID, operation)
+}
+
+func getHostControlSubject(lattice, providerID, hostID, operation string) string {
+	return fmt.Sprintf("%s.%s.%s.%s.%s", controlSubjectPrefix, lattice, providerID, hostID, operation)
+}
+
+// controlHandler answers a request on a control subject, on the given queue
+// group, or on every replica if it has none.
+type controlHandler struct {
+	queue  string
+	handle func(*nats.Msg) ControlResponse
+}
+
+// ServeControl subscribes to the control subjects of the provider, which
+// answer requests from operators over NATS request/reply.
+// Each replica keeps its own history and jobs, so every replica answers on
+// the provider's subjects with its host, and a request gathering several
+// replies covers all of them. A job is triggered on a single replica picked
+// by a queue group. Each replica also answers on subjects scoped to the host
+// it runs on, for requests meant for that replica alone.
+func (t *Ticker) ServeControl(nc *nats.Conn, lattice, providerID, hostID string) error {
+	if nc == nil {
+		return ErrNoConnection
+	}
+
+	handlers := map[string]controlHandler{
+		getControlSubject(lattice, providerID, controlHistory):     {handle: t.handleHistory},
+		getControlSubject(lattice, providerID, controlJobsList):    {handle: t.handleListJobs},
+		getControlSubject(lattice, providerID, controlJobsTrigger): {queue: controlQueue, handle: t.handleTriggerJob},
+		getControlSubject(lattice, providerID, controlJobsPause):   {handle: t.handlePauseJob},
+		getControlSubject(lattice, providerID, controlJobsResume):  {handle: t.handleResumeJob},
+
+		getHostControlSubject(lattice, providerID, hostID, controlHistory):     {handle: t.handleHistory},
+		getHostControlSubject(lattice, providerID, hostID, controlJobsList):    {handle: t.handleListJobs},
+		getHostControlSubject(lattice, providerID, hostID, controlJobsTrigger): {handle: t.handleTriggerJob},
+		getHostControlSubject(lattice, providerID, hostID, controlJobsPause):   {handle: t.replicaOnly(getControlSubject(lattice, providerID, controlJobsPause), t.handlePauseJob)},
+		getHostControlSubject(lattice, providerID, hostID, controlJobsResume):  {handle: t.replicaOnly(getControlSubject(lattice, providerID, controlJobsResume), t.handleResumeJob)},
+	}
+	for subject, handler := range handlers {
+		sub, err := nc.QueueSubscribe(subject, handler.queue, func(msg *nats.Msg) {
+			response := handler.handle(msg)
+			response.Host = hostID
+			t.respond(msg, response)
+		})
+		if err != nil {
+			return errors.Join(err, t.stopControl())
+		}
+		t.control = append(t.control, sub)
+	}
+	return nil
+}
+
+// replicaOnly rejects requests to change a job on a single replica while
+// replicas share their jobs, as the other replicas would carry on running
+// it. They are made on the given subject of the provider instead, which
+// every replica answers.
+func (t *Ticker) replicaOnly(subject string, handler func(*nats.Msg) ControlResponse) func(*nats.Msg) ControlResponse {
+	return func(msg *nats.Msg) ControlResponse {
+		if t.elector != nil || t.locker != nil {
+			err := fmt.Errorf("%w: request %s instead", ErrSharedJob, subject)
+			return ControlResponse{Error: err.Error()}
+		}
+		return handler(msg)
+	}
+}
+
+// stopControl unsubscribes from the control subjects.
+func (t *Ticker) stopControl() error {
+	errs := []error{}
+	for _, sub := range t.control {
+		errs = append(errs, sub.Unsubscribe())
+	}
+	t.control = nil
+	return errors.Join(errs...)
+}
+
+func (t *Ticker) respond(msg *nats.Msg, response ControlResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.logger().Error("error: json.Marshal", "error", err, "subject", msg.Subject)
+		return
+	}
+
+	err = msg.Respond(data)
+	if err != nil {
+		t.logger().Error("error: msg.Respond", "error", err, "subject", msg.Subject)
+	}
+}
+
+// handleHistory lists the run history of the provider's tasks, filtered by
+// the HistoryFilter in the request body, if any.
+func (t *Ticker) handleHistory(msg *nats.Msg) ControlResponse {
+	_, span := tracer.Start(context.Background(), "History")
+	defer span.End()
+
+	filter := HistoryFilter{}
+	if len(msg.Data) > 0 {
+		err := json.Unmarshal(msg.Data, &filter)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+			span.RecordError(err)
+			return ControlResponse{Error: err.Error()}
+		}
+	}
+	span.SetAttributes(
+		attribute.String("link", filter.Link),
+		attribute.String("component", filter.Component),
+	)
+
+	records := []RunRecord{}
+	if t.history != nil {
+		records = t.history.List(filter)
+	}
+	return ControlResponse{Records: records}
+}
+
+func parseJobRequest(msg *nats.Msg) (JobRequest, error) {
+	request := JobRequest{}
+	if len(msg.Data) == 0 {
+		return request, nil
+	}
+
+	err := json.Unmarshal(msg.Data, &request)
+	if err != nil {
+		return request, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return request, nil
+}
+
+// getRequestTask returns the task picked by the key or ID of a request.
+func (t *Ticker) getRequestTask(request JobRequest) (*TickerTask, error) {
+	switch {
+	case request.Key != "":
+		t.lock.RLock()
+		task, ok := t.taskList[request.Key]
+		t.lock.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("%w: key %s", ErrTickerNotFound, request.Key)
+		}
+		return task, nil
+	case request.ID != "":
+		id, err := uuid.Parse(request.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: id %s", ErrInvalidRequest, request.ID)
+		}
+		task, ok := t.getTaskByID(id)
+		if !ok {
+			return nil, fmt.Errorf("%w: id %s", ErrTickerNotFound, request.ID)
+		}
+		return task, nil
+	default:
+		return nil, fmt.Errorf("%w: key or id required", ErrInvalidRequest)
+	}
+}
+
+// handleListJobs lists the jobs of the provider, filtered by the link and
+// component of the request, if any.
+func (t *Ticker) handleListJobs(msg *nats.Msg) ControlResponse {
+	_, span := tracer.Start(context.Background(), "ListJobs")
+	defer span.End()
+
+	request, err := parseJobRequest(msg)
+	if err != nil {
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+
+	t.lock.RLock()
+	tasks := make([]*TickerTask, 0, len(t.taskList))
+	for _, task := range t.taskList {
+		if (request.Link == "" || request.Link == task.Link) && (request.Component == "" || request.Component == task.Component) {
+			tasks = append(tasks, task)
+		}
+	}
+	t.lock.RUnlock()
+
+	jobs := make([]JobStatus, 0, len(tasks))
+	for _, task := range tasks {
+		jobs = append(jobs, getJobStatus(task))
+	}
+	slices.SortFunc(jobs, func(a, b JobStatus) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return ControlResponse{Jobs: jobs}
+}
+
+// handleTriggerJob runs a job now on this replica, through the scheduler so
+// the run observes the job's distributed mode and overlap policy.
+func (t *Ticker) handleTriggerJob(msg *nats.Msg) ControlResponse {
+	_, span := tracer.Start(context.Background(), "TriggerJob")
+	defer span.End()
+
+	request, err := parseJobRequest(msg)
+	if err != nil {
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+
+	task, err := t.getRequestTask(request)
+	if err != nil {
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+	span.SetAttributes(attribute.String("key", task.key))
+
+	task.mu.Lock()
+	job := task.job
+	task.mu.Unlock()
+	if job == nil || task.ID == uuid.Nil {
+		err = fmt.Errorf("%w: key %s", ErrTaskNotScheduled, task.key)
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	} else if task.Paused() {
+		err = fmt.Errorf("%w: key %s", ErrTaskPaused, task.key)
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+
+	// The scheduler skips runs which this replica may not fire without
+	// reporting it, so they are rejected here instead
+	err = t.checkTrigger(task)
+	if err != nil {
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+
+	err = job.RunNow()
+	if err != nil {
+		t.logger().Error("error: RunNow", "error", err, "key", task.key)
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+
+	t.logger().Info("job triggered", "id", task.ID.String(), "key", task.key, "component", task.Component)
+	status := getJobStatus(task)
+	return ControlResponse{Job: &status}
+}
+
+// checkTrigger reports whether this replica may run a job now, which in
+// leader mode is only the leader, and in lock mode only while no other
+// replica holds the job's lock.
+func (t *Ticker) checkTrigger(task *TickerTask) error {
+	// Jobs scheduled by components at runtime do not need the leader
+	if t.elector != nil && !task.runtime && !t.elector.isLeader() {
+		leader, ok := t.elector.Leader()
+		if !ok {
+			leader = "unknown"
+		}
+		return fmt.Errorf("%w: key %s: leader %s", ErrNotLeader, task.key, leader)
+	}
+
+	if t.locker != nil {
+		held, err := t.locker.Held(context.Background(), task.key)
+		if err != nil {
+			return err
+		} else if held {
+			return fmt.Errorf("%w: key %s", ErrLockHeld, task.key)
+		}
+	}
+	return nil
+}
+
+// handlePauseJob pauses a job, so its runs are skipped until it is resumed.
+// Replicas which share their jobs are all paused by a request on the
+// provider's subject.
+func (t *Ticker) handlePauseJob(msg *nats.Msg) ControlResponse {
+	return t.setJobPaused(msg, "PauseJob", true)
+}
+
+// handleResumeJob resumes a paused job from its next scheduled run.
+func (t *Ticker) handleResumeJob(msg *nats.Msg) ControlResponse {
+	return t.setJobPaused(msg, "ResumeJob", false)
+}
+
+// setJobPaused pauses or resumes the job picked by a request, saving it in
+// the job's state so it is kept over link updates and restarts.
+func (t *Ticker) setJobPaused(msg *nats.Msg, name string, paused bool) ControlResponse {
+	_, span := tracer.Start(context.Background(), name)
+	defer span.End()
+
+	request, err := parseJobRequest(msg)
+	if err != nil {
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+
+	task, err := t.getRequestTask(request)
+	if err != nil {
+		span.RecordError(err)
+		return ControlResponse{Error: err.Error()}
+	}
+	span.SetAttributes(attribute.String("key", task.key))
+
+	if task.setPaused(paused) {
+		t.saveState(task)
+
+		message := "job resumed"
+		if paused {
+			message = "job paused"
+		}
+		t.logger().Info(message, "id", task.ID.String(), "key", task.key, "component", task.Component)
+	}
+
+	status := getJobStatus(task)
+	return ControlResponse{Job: &status}
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gocronmocks "github.com/go-co-op/gocron/mocks/v2"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.wasmcloud.dev/provider"
+)
+
+func controlRequest(t *testing.T, nc *nats.Conn, operation, data string) ControlResponse {
+	t.Helper()
+
+	msg, err := nc.Request(getHostControlSubject("default", "ticker-provider", "my-host", operation), []byte(data), time.Second)
+	if !assert.NoError(t, err) {
+		return ControlResponse{}
+	}
+
+	response := ControlResponse{}
+	assert.NoError(t, json.Unmarshal(msg.Data, &response))
+	return response
+}
+
+func TestServeControl(t *testing.T) {
+	t.Run("no connection", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.ServeControl(nil, "default", "ticker-provider", "my-host")
+		assert.ErrorIs(t, err, ErrNoConnection)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+
+		startedAt := time.Now().UTC()
+		assert.NoError(t, ticker.history.Add("default.my-component", 10, RunRecord{
+			Component: "my-component",
+			Link:      "default",
+			StartedAt: startedAt,
+			Attempts:  1,
+			Outcome:   runOutcomeSuccess,
+		}))
+		assert.NoError(t, ticker.history.Add("nightly.other-component", 10, RunRecord{
+			Component: "other-component",
+			Link:      "nightly",
+			StartedAt: startedAt,
+			Attempts:  3,
+			Outcome:   runOutcomeFailure,
+			Error:     "error ticker task failed: test error",
+		}))
+
+		err = ticker.ServeControl(nc, "default", "ticker-provider", "my-host")
+		assert.NoError(t, err)
+
+		request := func(data string) ControlResponse {
+			return controlRequest(t, nc, controlHistory, data)
+		}
+
+		response := request("")
+		assert.Empty(t, response.Error)
+		assert.Equal(t, "my-host", response.Host)
+		assert.Len(t, response.Records, 2)
+
+		response = request(`{"component": "other-component"}`)
+		assert.Len(t, response.Records, 1)
+		assert.Equal(t, "nightly", response.Records[0].Link)
+		assert.Equal(t, uint32(3), response.Records[0].Attempts)
+		assert.Equal(t, "error ticker task failed: test error", response.Records[0].Error)
+		assert.True(t, startedAt.Equal(response.Records[0].StartedAt))
+
+		response = request(`{"link": "default", "outcome": "failure"}`)
+		assert.Empty(t, response.Records)
+
+		response = request(`{"link":`)
+		assert.Contains(t, response.Error, ErrInvalidRequest.Error())
+
+		// Control subjects are unsubscribed on shutdown
+		assert.NoError(t, ticker.Shutdown())
+		_, err = nc.Request("wasmbus.ticker.default.ticker-provider.my-host.history", nil, 100*time.Millisecond)
+		assert.Error(t, err)
+	})
+
+	t.Run("replicas", func(t *testing.T) {
+		nc := runNatsServer(t)
+		for _, host := range []string{"host-a", "host-b"} {
+			ticker, err := CreateTicker()
+			assert.NoError(t, err)
+			ticker.provider = &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			}
+			assert.NoError(t, ticker.history.Add("default.my-component", 10, RunRecord{
+				Component: "my-component",
+				Link:      "default",
+				Error:     host,
+				Outcome:   runOutcomeFailure,
+			}))
+			assert.NoError(t, ticker.ServeControl(nc, "default", "ticker-provider", host))
+			defer ticker.Shutdown()
+		}
+
+		// Each replica only answers for the runs it fired
+		for _, host := range []string{"host-a", "host-b"} {
+			msg, err := nc.Request(getHostControlSubject("default", "ticker-provider", host, controlHistory), nil, time.Second)
+			assert.NoError(t, err)
+
+			response := ControlResponse{}
+			assert.NoError(t, json.Unmarshal(msg.Data, &response))
+			assert.Equal(t, host, response.Host)
+			if assert.Len(t, response.Records, 1) {
+				assert.Equal(t, host, response.Records[0].Error)
+			}
+		}
+	})
+
+	t.Run("jobs", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctrl := gomock.NewController(t)
+		j := gocronmocks.NewMockJob(ctrl)
+
+		nextRun := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+		j.EXPECT().NextRun().Return(nextRun, nil).AnyTimes()
+		j.EXPECT().RunNow().Return(nil).Times(1)
+
+		state, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		ticker.state = state
+
+		task := &TickerTask{
+			Component: "my-component",
+			ID:        uuid.New(),
+			Link:      "default",
+			Type:      "interval",
+			Config:    map[string]string{"type": "interval", "period": "1m"},
+			key:       "default.my-component",
+			job:       j,
+		}
+		// Jobs which have already run to completion are kept without a job
+		finished := &TickerTask{
+			Component: "other-component",
+			Link:      "nightly",
+			Type:      "once",
+			Config:    map[string]string{"type": "once", "times": "2026-01-01T00:00:00Z"},
+			key:       "nightly.other-component",
+		}
+		ticker.taskList[task.key] = task
+		ticker.taskList[finished.key] = finished
+
+		err = ticker.ServeControl(nc, "default", "ticker-provider", "my-host")
+		assert.NoError(t, err)
+		defer ticker.Shutdown()
+
+		response := controlRequest(t, nc, controlJobsList, "")
+		assert.Empty(t, response.Error)
+		if assert.Len(t, response.Jobs, 2) {
+			assert.Equal(t, "default.my-component", response.Jobs[0].Key)
+			assert.Equal(t, task.ID.String(), response.Jobs[0].ID)
+			assert.Equal(t, "1m", response.Jobs[0].Description)
+			assert.False(t, response.Jobs[0].Paused)
+			if assert.NotNil(t, response.Jobs[0].NextRun) {
+				assert.True(t, nextRun.Equal(*response.Jobs[0].NextRun))
+			}
+
+			assert.Equal(t, "nightly.other-component", response.Jobs[1].Key)
+			assert.Empty(t, response.Jobs[1].ID)
+			assert.Nil(t, response.Jobs[1].NextRun)
+		}
+
+		response = controlRequest(t, nc, controlJobsList, `{"component": "other-component"}`)
+		assert.Len(t, response.Jobs, 1)
+
+		response = controlRequest(t, nc, controlJobsTrigger, `{"key": "nightly.other-component"}`)
+		assert.Contains(t, response.Error, ErrTaskNotScheduled.Error())
+
+		response = controlRequest(t, nc, controlJobsTrigger, `{"key": "missing.my-component"}`)
+		assert.Contains(t, response.Error, ErrTickerNotFound.Error())
+
+		response = controlRequest(t, nc, controlJobsTrigger, "")
+		assert.Contains(t, response.Error, ErrInvalidRequest.Error())
+
+		// Paused jobs skip their runs and cannot be triggered
+		response = controlRequest(t, nc, controlJobsPause, fmt.Sprintf(`{"id": "%s"}`, task.ID))
+		assert.Empty(t, response.Error)
+		if assert.NotNil(t, response.Job) {
+			assert.True(t, response.Job.Paused)
+			assert.Nil(t, response.Job.NextRun)
+		}
+
+		saved, ok, err := state.Load(context.Background(), task.key)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, saved.Paused)
+
+		response = controlRequest(t, nc, controlJobsTrigger, `{"key": "default.my-component"}`)
+		assert.Contains(t, response.Error, ErrTaskPaused.Error())
+
+		err = ticker.TaskFunc(context.Background(), task)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), task.skipped)
+		assert.Equal(t, uint64(0), task.State().RunCount)
+
+		response = controlRequest(t, nc, controlJobsResume, `{"key": "default.my-component"}`)
+		assert.Empty(t, response.Error)
+		if assert.NotNil(t, response.Job) {
+			assert.False(t, response.Job.Paused)
+			assert.NotNil(t, response.Job.NextRun)
+		}
+
+		response = controlRequest(t, nc, controlJobsTrigger, `{"key": "default.my-component"}`)
+		assert.Empty(t, response.Error)
+		assert.Equal(t, "my-host", response.Host)
+		assert.NotNil(t, response.Job)
+	})
+
+	t.Run("trigger replicas", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctrl := gomock.NewController(t)
+
+		// Only the replica on the requested host runs the job
+		for host, runs := range map[string]int{"host-a": 1, "host-b": 0} {
+			j := gocronmocks.NewMockJob(ctrl)
+			j.EXPECT().NextRun().Return(time.Now().Add(time.Minute), nil).AnyTimes()
+			j.EXPECT().RunNow().Return(nil).Times(runs)
+
+			ticker, err := CreateTicker()
+			assert.NoError(t, err)
+			ticker.provider = &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			}
+			ticker.taskList["default.my-component"] = &TickerTask{
+				Component: "my-component",
+				ID:        uuid.New(),
+				Link:      "default",
+				Type:      "interval",
+				Config:    map[string]string{"type": "interval", "period": "1m"},
+				key:       "default.my-component",
+				job:       j,
+			}
+			assert.NoError(t, ticker.ServeControl(nc, "default", "ticker-provider", host))
+			defer ticker.Shutdown()
+		}
+
+		msg, err := nc.Request(getHostControlSubject("default", "ticker-provider", "host-a", controlJobsTrigger), []byte(`{"key": "default.my-component"}`), time.Second)
+		assert.NoError(t, err)
+
+		response := ControlResponse{}
+		assert.NoError(t, json.Unmarshal(msg.Data, &response))
+		assert.Empty(t, response.Error)
+		assert.Equal(t, "host-a", response.Host)
+	})
+
+	t.Run("provider subjects", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctrl := gomock.NewController(t)
+
+		var runs atomic.Int32
+		for _, host := range []string{"host-a", "host-b"} {
+			j := gocronmocks.NewMockJob(ctrl)
+			j.EXPECT().NextRun().Return(time.Now().Add(time.Minute), nil).AnyTimes()
+			j.EXPECT().RunNow().DoAndReturn(func() error {
+				runs.Add(1)
+				return nil
+			}).AnyTimes()
+
+			ticker, err := CreateTicker()
+			assert.NoError(t, err)
+			ticker.provider = &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			}
+			ticker.taskList["default.my-component"] = &TickerTask{
+				Component: "my-component",
+				ID:        uuid.New(),
+				Link:      "default",
+				Type:      "interval",
+				Config:    map[string]string{"type": "interval", "period": "1m"},
+				key:       "default.my-component",
+				job:       j,
+			}
+			assert.NoError(t, ticker.ServeControl(nc, "default", "ticker-provider", host))
+			defer ticker.Shutdown()
+		}
+
+		// Every replica answers a request for its jobs
+		inbox := nc.NewRespInbox()
+		sub, err := nc.SubscribeSync(inbox)
+		assert.NoError(t, err)
+		defer sub.Unsubscribe()
+		err = nc.PublishRequest(getControlSubject("default", "ticker-provider", controlJobsList), inbox, nil)
+		assert.NoError(t, err)
+
+		hosts := []string{}
+		for range 2 {
+			msg, err := sub.NextMsg(time.Second)
+			if !assert.NoError(t, err) {
+				break
+			}
+			response := ControlResponse{}
+			assert.NoError(t, json.Unmarshal(msg.Data, &response))
+			assert.Len(t, response.Jobs, 1)
+			hosts = append(hosts, response.Host)
+		}
+		assert.ElementsMatch(t, []string{"host-a", "host-b"}, hosts)
+
+		// Only one replica runs a triggered job
+		msg, err := nc.Request(getControlSubject("default", "ticker-provider", controlJobsTrigger), []byte(`{"key": "default.my-component"}`), time.Second)
+		assert.NoError(t, err)
+
+		response := ControlResponse{}
+		assert.NoError(t, json.Unmarshal(msg.Data, &response))
+		assert.Empty(t, response.Error)
+		assert.Contains(t, []string{"host-a", "host-b"}, response.Host)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), runs.Load())
+	})
+
+	t.Run("leader", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctrl := gomock.NewController(t)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Now().Add(time.Minute), nil).AnyTimes()
+		j.EXPECT().RunNow().Times(0)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		ticker.elector = &NatsElector{
+			id:     "my-host",
+			leader: "host-a",
+		}
+		task := &TickerTask{
+			Component: "my-component",
+			ID:        uuid.New(),
+			Link:      "default",
+			Type:      "interval",
+			Config:    map[string]string{"type": "interval", "period": "1m"},
+			key:       "default.my-component",
+			job:       j,
+		}
+		ticker.taskList[task.key] = task
+		assert.NoError(t, ticker.ServeControl(nc, "default", "ticker-provider", "my-host"))
+		defer ticker.Shutdown()
+
+		// Only the leader runs link jobs, so it is named for the request
+		response := controlRequest(t, nc, controlJobsTrigger, `{"key": "default.my-component"}`)
+		assert.Contains(t, response.Error, ErrNotLeader.Error())
+		assert.Contains(t, response.Error, "leader host-a")
+
+		// Pausing one replica would leave the leader running the job
+		response = controlRequest(t, nc, controlJobsPause, `{"key": "default.my-component"}`)
+		assert.Contains(t, response.Error, ErrSharedJob.Error())
+		assert.Contains(t, response.Error, getControlSubject("default", "ticker-provider", controlJobsPause))
+		assert.False(t, task.Paused())
+
+		msg, err := nc.Request(getControlSubject("default", "ticker-provider", controlJobsPause), []byte(`{"key": "default.my-component"}`), time.Second)
+		assert.NoError(t, err)
+
+		response = ControlResponse{}
+		assert.NoError(t, json.Unmarshal(msg.Data, &response))
+		assert.Empty(t, response.Error)
+		assert.True(t, task.Paused())
+	})
+
+	t.Run("lock held", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctrl := gomock.NewController(t)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Now().Add(time.Minute), nil).AnyTimes()
+		j.EXPECT().RunNow().Return(nil).Times(1)
+
+		locker, err := NewNatsLocker(context.Background(), nc, "test_lock", time.Minute, 0)
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		ticker.locker = locker
+		ticker.taskList["default.my-component"] = &TickerTask{
+			Component: "my-component",
+			ID:        uuid.New(),
+			Link:      "default",
+			Type:      "interval",
+			Config:    map[string]string{"type": "interval", "period": "1m"},
+			key:       "default.my-component",
+			job:       j,
+		}
+		assert.NoError(t, ticker.ServeControl(nc, "default", "ticker-provider", "my-host"))
+		defer ticker.Shutdown()
+
+		// A job running on another replica holds its lock
+		lock, err := locker.Lock(context.Background(), "default.my-component")
+		assert.NoError(t, err)
+
+		response := controlRequest(t, nc, controlJobsTrigger, `{"key": "default.my-component"}`)
+		assert.Contains(t, response.Error, ErrLockHeld.Error())
+
+		assert.NoError(t, lock.Unlock(context.Background()))
+		response = controlRequest(t, nc, controlJobsTrigger, `{"key": "default.my-component"}`)
+		assert.Empty(t, response.Error)
+	})
+}
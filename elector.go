@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	leaderKey = "leader"
+)
+
+var (
+	ErrNotLeader = errors.New("error replica is not the leader")
+
+	_ gocron.Elector = (*NatsElector)(nil)
+)
+
+// NatsElector is a gocron.Elector which elects a single leader between
+// provider replicas, using a lease held on a NATS JetStream key-value bucket.
+//
+// The leader renews its lease three times per lease period. If it stops
+// renewing, the bucket TTL expires the lease and another replica takes over.
+type NatsElector struct {
+	kv     jetstream.KeyValue
+	id     string
+	lease  time.Duration
+	logger *slog.Logger
+
+	mu      sync.RWMutex
+	leader  string
+	renewed time.Time
+
+	revision uint64
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewNatsElector creates the leader bucket if it does not exist, updating its
+// TTL to the lease duration if it does.
+func NewNatsElector(ctx context.Context, nc *nats.Conn, bucket, id string, lease time.Duration, logger *slog.Logger) (*NatsElector, error) {
+	if nc == nil {
+		return nil, ErrNoConnection
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "ticker-provider leader lease",
+		TTL:         lease,
+		History:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &NatsElector{
+		kv:     kv,
+		id:     id,
+		lease:  lease,
+		logger: logger,
+	}, nil
+}
+
+// Start campaigns for leadership until Stop is called. The first campaign
+// completes before Start returns, so jobs due straight away see the result.
+func (e *NatsElector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	e.campaign(ctx)
+
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			e.campaign(ctx)
+		}
+	}()
+}
+
+// Stop ends the campaign, giving up the lease if this replica holds it so
+// another replica can take over straight away.
+func (e *NatsElector) Stop() error {
+	if e.cancel == nil {
+		return nil
+	}
+	e.cancel()
+	<-e.done
+
+	if !e.isLeader() {
+		return nil
+	}
+	e.setLeader("")
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.lease/3)
+	defer cancel()
+	err := e.kv.Delete(ctx, leaderKey, jetstream.LastRevision(e.revision))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (e *NatsElector) IsLeader(_ context.Context) error {
+	if !e.isLeader() {
+		return ErrNotLeader
+	}
+	return nil
+}
+
+// ID returns the identity this replica campaigns with.
+func (e *NatsElector) ID() string {
+	return e.id
+}
+
+// Leader returns the identity of the current leader, if one is known.
+func (e *NatsElector) Leader() (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader, e.leader != ""
+}
+
+// isLeader reports whether this replica holds a lease which has not expired.
+func (e *NatsElector) isLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader == e.id && time.Since(e.renewed) < e.lease
+}
+
+// campaign renews the lease if this replica holds it, otherwise it tries to
+// take the lease and records the current leader.
+func (e *NatsElector) campaign(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, e.lease/3)
+	defer cancel()
+
+	if e.isLeader() {
+		revision, err := e.kv.Update(ctx, leaderKey, []byte(e.id), e.revision)
+		if err == nil {
+			e.revision = revision
+			e.renew()
+			return
+		}
+		e.logger.Warn("leader lease lost", "error", err, "id", e.id)
+	}
+
+	revision, err := e.kv.Create(ctx, leaderKey, []byte(e.id))
+	if err == nil {
+		e.revision = revision
+		e.renew()
+		e.setLeader(e.id)
+		return
+	}
+
+	entry, err := e.kv.Get(ctx, leaderKey)
+	if err != nil {
+		e.setLeader("")
+		return
+	}
+	e.setLeader(string(entry.Value()))
+}
+
+func (e *NatsElector) renew() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.renewed = time.Now()
+}
+
+func (e *NatsElector) setLeader(leader string) {
+	e.mu.Lock()
+	previous := e.leader
+	e.leader = leader
+	e.mu.Unlock()
+
+	if leader == previous {
+		return
+	}
+	if leader == "" {
+		e.logger.Warn("leader unknown", "id", e.id, "previous", previous)
+		return
+	}
+	e.logger.Info("leader elected", "id", e.id, "leader", leader, "is_leader", leader == e.id)
+}
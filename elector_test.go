@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNatsElector(t *testing.T) {
+	t.Run("single leader", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctx := context.Background()
+
+		first, err := NewNatsElector(ctx, nc, "test_leader", "host-a", time.Second, slog.Default())
+		assert.NoError(t, err)
+		second, err := NewNatsElector(ctx, nc, "test_leader", "host-b", time.Second, slog.Default())
+		assert.NoError(t, err)
+
+		first.Start()
+		defer first.Stop()
+		assert.Eventually(t, func() bool {
+			return first.IsLeader(ctx) == nil
+		}, 5*time.Second, 50*time.Millisecond)
+
+		second.Start()
+		defer second.Stop()
+		assert.Eventually(t, func() bool {
+			leader, ok := second.Leader()
+			return ok && leader == "host-a"
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.ErrorIs(t, second.IsLeader(ctx), ErrNotLeader)
+
+		// Leadership is held across several lease periods
+		time.Sleep(1500 * time.Millisecond)
+		assert.NoError(t, first.IsLeader(ctx))
+		assert.ErrorIs(t, second.IsLeader(ctx), ErrNotLeader)
+	})
+
+	t.Run("failover on stop", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctx := context.Background()
+
+		first, err := NewNatsElector(ctx, nc, "test_leader", "host-a", time.Second, slog.Default())
+		assert.NoError(t, err)
+		second, err := NewNatsElector(ctx, nc, "test_leader", "host-b", time.Second, slog.Default())
+		assert.NoError(t, err)
+
+		first.Start()
+		assert.Eventually(t, func() bool {
+			return first.IsLeader(ctx) == nil
+		}, 5*time.Second, 50*time.Millisecond)
+
+		second.Start()
+		defer second.Stop()
+
+		assert.NoError(t, first.Stop())
+		assert.ErrorIs(t, first.IsLeader(ctx), ErrNotLeader)
+		assert.Eventually(t, func() bool {
+			return second.IsLeader(ctx) == nil
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("failover on lease expiry", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctx := context.Background()
+
+		elector, err := NewNatsElector(ctx, nc, "test_leader", "host-b", time.Second, slog.Default())
+		assert.NoError(t, err)
+
+		// Simulate a leader which stopped renewing its lease
+		js, err := jetstream.New(nc)
+		assert.NoError(t, err)
+		kv, err := js.KeyValue(ctx, "test_leader")
+		assert.NoError(t, err)
+		_, err = kv.Create(ctx, leaderKey, []byte("host-a"))
+		assert.NoError(t, err)
+
+		elector.Start()
+		defer elector.Stop()
+		assert.Eventually(t, func() bool {
+			leader, ok := elector.Leader()
+			return ok && leader == "host-a"
+		}, 5*time.Second, 50*time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			return elector.IsLeader(ctx) == nil
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("no connection", func(t *testing.T) {
+		_, err := NewNatsElector(context.Background(), nil, "test_leader", "host-a", time.Second, slog.Default())
+		assert.ErrorIs(t, err, ErrNoConnection)
+	})
+}
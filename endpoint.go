@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.wasmcloud.dev/provider"
+)
+
+var ErrMetricsExported = errors.New("metrics are already exported over OTLP")
+
+// newPrometheusGatherer exports the provider's metrics in Prometheus format
+// by installing a meter provider for them. Metrics which the host already
+// exports over OTLP cannot be exported again, so the listener is refused
+// rather than serving none of the provider's metrics.
+func newPrometheusGatherer() (prometheus.Gatherer, error) {
+	if otel.GetMeterProvider() != defaultMeterProvider {
+		return nil, ErrMetricsExported
+	}
+
+	registry := prometheus.NewRegistry()
+	err := registry.Register(collectors.NewGoCollector())
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, err
+	}
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter)))
+	return registry, nil
+}
+
+// metricsHandler serves the metrics of the provider at /metrics and its
+// health at /healthz, which responds with 503 Service Unavailable while the
+// provider is unhealthy.
+func (t *Ticker) metricsHandler(gatherer prometheus.Gatherer) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		body := t.handleHealthCheck()
+
+		status := http.StatusOK
+		var health provider.HealthCheckResponse
+		if err := json.Unmarshal([]byte(body), &health); err != nil || !health.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	return mux
+}
+
+// serveMetrics starts an HTTP listener for the metrics and health of the
+// provider on the given address.
+func (t *Ticker) serveMetrics(address string, gatherer prometheus.Gatherer) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:    listener.Addr().String(),
+		Handler: t.metricsHandler(gatherer),
+	}
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.logger().Error("error: metrics server", "error", err, "address", address)
+		}
+	}()
+
+	t.logger().Info("serving metrics", "address", server.Addr)
+	t.server = server
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.wasmcloud.dev/provider"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	installMeterProvider(t)
+
+	windows, err := parseBlackouts("0 * * * * for 1h", nil)
+	assert.NoError(t, err)
+
+	ticker, err := CreateTicker()
+	assert.NoError(t, err)
+	ticker.provider = &provider.WasmcloudProvider{
+		Logger: slog.Default(),
+	}
+	defer ticker.Shutdown()
+
+	err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+		Name:     "default",
+		SourceID: "scraped-component",
+		TargetConfig: map[string]string{
+			"period": "1h",
+		},
+	})
+	assert.NoError(t, err)
+	ticker.Start()
+
+	err = ticker.TaskFunc(context.Background(), &TickerTask{
+		Component: "scraped-component",
+		ID:        uuid.New(),
+		Link:      "default",
+		Type:      "interval",
+		Blackouts: windows,
+	})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(ticker.metricsHandler(metricRegistry))
+	defer server.Close()
+
+	t.Run("metrics", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/metrics")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `ticker_task_skipped_total{component="scraped-component",link="default",otel_scope_name="ticker-provider",otel_scope_version="",reason="blackout",type="interval"} 1`)
+		assert.Contains(t, string(body), `ticker_jobs_registered{component="scraped-component",link="default",otel_scope_name="ticker-provider",otel_scope_version="",type="interval"} 1`)
+	})
+
+	t.Run("healthz", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/healthz")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var health provider.HealthCheckResponse
+		err = json.NewDecoder(resp.Body).Decode(&health)
+		assert.NoError(t, err)
+		assert.True(t, health.Healthy)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/metrics", "text/plain", nil)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+		resp, err = http.Get(server.URL + "/")
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestServeMetrics(t *testing.T) {
+	installMeterProvider(t)
+
+	ticker, err := CreateTicker()
+	assert.NoError(t, err)
+
+	err = ticker.serveMetrics("127.0.0.1:0", metricRegistry)
+	assert.NoError(t, err)
+	assert.NotNil(t, ticker.server)
+
+	resp, err := http.Get("http://" + ticker.server.Addr + "/healthz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	ticker.Start()
+	resp, err = http.Get("http://" + ticker.server.Addr + "/healthz")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	err = ticker.Shutdown()
+	assert.NoError(t, err)
+	assert.Nil(t, ticker.server)
+
+	ticker, err = CreateTicker()
+	assert.NoError(t, err)
+	err = ticker.serveMetrics("not an address", metricRegistry)
+	assert.Error(t, err)
+	assert.Nil(t, ticker.server)
+
+	// The meter provider installed above stands in for the host's OTLP one,
+	// which leaves the endpoint out
+	_, err = newPrometheusGatherer()
+	assert.ErrorIs(t, err, ErrMetricsExported)
+
+	ticker.provider = &provider.WasmcloudProvider{
+		Logger: slog.Default(),
+	}
+	err = ticker.Configure(map[string]string{
+		"metrics_address": "127.0.0.1:0",
+		"state_store":     "none",
+	}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, ticker.server)
+}
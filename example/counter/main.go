@@ -62,8 +62,8 @@ func handleRequest(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
-func handleTask() ticker.TaskError {
-	logger.Info("handleTask")
+func handleTask(invocation ticker.InvocationContext) ticker.TaskError {
+	logger.Info("handleTask", "job", invocation.JobID, "link", invocation.LinkName, "run", invocation.RunCount, "attempt", invocation.Attempt)
 
 	bucket, _, isErr := store.Open("").Result()
 	if isErr {
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	runOutcomeSuccess = "success"
+	runOutcomeFailure = "failure"
+	runOutcomeSkipped = "skipped"
+
+	// Changes to the history are written to its file at most this often
+	historyFlushDelay = time.Second
+)
+
+// RunRecord is the record of a single run of a task, kept in its history.
+type RunRecord struct {
+	Key         string    `json:"key"`
+	Component   string    `json:"component"`
+	Link        string    `json:"link"`
+	Schedule    string    `json:"schedule,omitempty"`
+	Type        string    `json:"type"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+	Attempts    uint32    `json:"attempts"`
+	Outcome     string    `json:"outcome"`
+	Error       string    `json:"error,omitempty"`
+	TraceID     string    `json:"trace_id,omitempty"`
+	CatchUp     bool      `json:"catch_up,omitempty"`
+}
+
+// HistoryFilter selects the records returned from the run history. Empty
+// fields match every record.
+type HistoryFilter struct {
+	Link      string    `json:"link,omitempty"`
+	Component string    `json:"component,omitempty"`
+	Schedule  string    `json:"schedule,omitempty"`
+	Outcome   string    `json:"outcome,omitempty"`
+	Since     time.Time `json:"since,omitempty"`
+	Limit     int       `json:"limit,omitempty"`
+}
+
+func (f HistoryFilter) matches(record RunRecord) bool {
+	return (f.Link == "" || f.Link == record.Link) &&
+		(f.Component == "" || f.Component == record.Component) &&
+		(f.Schedule == "" || f.Schedule == record.Schedule) &&
+		(f.Outcome == "" || f.Outcome == record.Outcome) &&
+		(f.Since.IsZero() || !record.StartedAt.Before(f.Since))
+}
+
+// traceID returns the ID of the trace a span belongs to, if it is recorded.
+func traceID(span trace.Span) string {
+	sc := span.SpanContext()
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// runRing is a fixed size ring buffer of run records, which overwrites the
+// oldest record once full.
+type runRing struct {
+	records []RunRecord
+	start   int
+	count   int
+}
+
+func newRunRing(size int, records []RunRecord) *runRing {
+	r := &runRing{
+		records: make([]RunRecord, size),
+	}
+	for _, record := range records {
+		r.add(record)
+	}
+	return r
+}
+
+func (r *runRing) add(record RunRecord) {
+	if len(r.records) == 0 {
+		return
+	}
+
+	end := (r.start + r.count) % len(r.records)
+	r.records[end] = record
+	if r.count < len(r.records) {
+		r.count++
+	} else {
+		r.start = (r.start + 1) % len(r.records)
+	}
+}
+
+// list returns the records from oldest to newest.
+func (r *runRing) list() []RunRecord {
+	records := make([]RunRecord, 0, r.count)
+	for i := 0; i < r.count; i++ {
+		records = append(records, r.records[(r.start+i)%len(r.records)])
+	}
+	return records
+}
+
+// RunHistory keeps the most recent runs of each task, keyed by job key, and
+// optionally persists them to a JSON file so they survive a restart. The file
+// is written in the background shortly after the history changes, so runs
+// are not held up by it, and when the history is closed.
+type RunHistory struct {
+	path  string
+	mu    sync.Mutex
+	rings map[string]*runRing
+	timer *time.Timer
+	err   error
+
+	// flushMu orders writes of the file, so an older copy of the history
+	// never replaces a newer one
+	flushMu sync.Mutex
+}
+
+// NewRunHistory loads the history from the file at path, if set. A missing
+// file is an empty history.
+func NewRunHistory(path string) (*RunHistory, error) {
+	h := &RunHistory{
+		path:  path,
+		rings: make(map[string]*runRing),
+	}
+	if path == "" {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	records := map[string][]RunRecord{}
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records are kept until the task is put again with its history size
+	for key, list := range records {
+		h.rings[key] = newRunRing(len(list), list)
+	}
+	return h, nil
+}
+
+// Add records a run of the task with the given key, keeping at most size
+// records for it. A size of zero disables the history of the task.
+func (h *RunHistory) Add(key string, size int, record RunRecord) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.resize(key, size)
+	ring, ok := h.rings[key]
+	if !ok {
+		return nil
+	}
+	ring.add(record)
+	return h.changed()
+}
+
+// Resize changes the number of records kept for a task, dropping the oldest
+// records if it shrinks.
+func (h *RunHistory) Resize(key string, size int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.resize(key, size) {
+		return nil
+	}
+	return h.changed()
+}
+
+func (h *RunHistory) resize(key string, size int) bool {
+	ring, ok := h.rings[key]
+	switch {
+	case size <= 0:
+		delete(h.rings, key)
+		return ok
+	case !ok:
+		h.rings[key] = newRunRing(size, nil)
+		return false
+	case len(ring.records) != size:
+		h.rings[key] = newRunRing(size, ring.list())
+		return true
+	}
+	return false
+}
+
+// Delete forgets the history of a task once its link is removed.
+func (h *RunHistory) Delete(key string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.rings[key]; !ok {
+		return nil
+	}
+	delete(h.rings, key)
+	return h.changed()
+}
+
+// List returns the records matching the filter from newest to oldest, up to
+// the filter's limit if set.
+func (h *RunHistory) List(filter HistoryFilter) []RunRecord {
+	h.mu.Lock()
+	records := []RunRecord{}
+	for _, ring := range h.rings {
+		for _, record := range ring.list() {
+			if filter.matches(record) {
+				records = append(records, record)
+			}
+		}
+	}
+	h.mu.Unlock()
+
+	slices.SortStableFunc(records, func(a, b RunRecord) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records
+}
+
+// changed schedules the history to be written to its file, returning the
+// error of the last write, if it failed.
+// The history's lock must be held.
+func (h *RunHistory) changed() error {
+	if h.path == "" {
+		return nil
+	}
+
+	if h.timer == nil {
+		h.timer = time.AfterFunc(historyFlushDelay, func() {
+			err := h.flush()
+			h.mu.Lock()
+			h.err = err
+			h.mu.Unlock()
+		})
+	}
+
+	err := h.err
+	h.err = nil
+	return err
+}
+
+// Close writes any changes to the history which are still to be written,
+// once any write in progress has finished.
+func (h *RunHistory) Close() error {
+	h.flushMu.Lock()
+	defer h.flushMu.Unlock()
+
+	h.mu.Lock()
+	pending := h.timer != nil
+	if pending {
+		h.timer.Stop()
+	}
+	h.mu.Unlock()
+	if !pending {
+		return nil
+	}
+	return h.write()
+}
+
+func (h *RunHistory) flush() error {
+	h.flushMu.Lock()
+	defer h.flushMu.Unlock()
+	return h.write()
+}
+
+// write writes the history to a temporary file and renames it over the
+// previous one, in the same way as the FileStore.
+// The history's flush lock must be held.
+func (h *RunHistory) write() error {
+	h.mu.Lock()
+	h.timer = nil
+	records := make(map[string][]RunRecord, len(h.rings))
+	for key, ring := range h.rings {
+		records[key] = ring.list()
+	}
+	h.mu.Unlock()
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(h.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp := h.path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
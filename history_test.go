@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHistory(t *testing.T) {
+	start := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	record := func(link string, i int, outcome string) RunRecord {
+		return RunRecord{
+			Key:       link + ".my-component",
+			Component: "my-component",
+			Link:      link,
+			StartedAt: start.Add(time.Duration(i) * time.Minute),
+			Outcome:   outcome,
+		}
+	}
+
+	t.Run("bounded", func(t *testing.T) {
+		history, err := NewRunHistory("")
+		assert.NoError(t, err)
+
+		for i := range 5 {
+			assert.NoError(t, history.Add("default.my-component", 3, record("default", i, runOutcomeSuccess)))
+		}
+
+		records := history.List(HistoryFilter{})
+		assert.Len(t, records, 3)
+		assert.Equal(t, start.Add(4*time.Minute), records[0].StartedAt)
+		assert.Equal(t, start.Add(2*time.Minute), records[2].StartedAt)
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		history, err := NewRunHistory("")
+		assert.NoError(t, err)
+
+		for i := range 4 {
+			assert.NoError(t, history.Add("default.my-component", 4, record("default", i, runOutcomeSuccess)))
+		}
+
+		// Shrinking keeps the newest records
+		assert.NoError(t, history.Resize("default.my-component", 2))
+		records := history.List(HistoryFilter{})
+		assert.Len(t, records, 2)
+		assert.Equal(t, start.Add(3*time.Minute), records[0].StartedAt)
+
+		assert.NoError(t, history.Resize("default.my-component", 0))
+		assert.Empty(t, history.List(HistoryFilter{}))
+
+		// A size of zero keeps nothing
+		assert.NoError(t, history.Add("default.my-component", 0, record("default", 5, runOutcomeSuccess)))
+		assert.Empty(t, history.List(HistoryFilter{}))
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		history, err := NewRunHistory("")
+		assert.NoError(t, err)
+
+		assert.NoError(t, history.Add("default.my-component", 10, record("default", 0, runOutcomeSuccess)))
+		assert.NoError(t, history.Add("default.my-component", 10, record("default", 1, runOutcomeFailure)))
+		assert.NoError(t, history.Add("nightly.my-component", 10, record("nightly", 2, runOutcomeSuccess)))
+		assert.NoError(t, history.Add("nightly.my-component", 10, record("nightly", 3, runOutcomeSkipped)))
+
+		assert.Len(t, history.List(HistoryFilter{}), 4)
+		assert.Len(t, history.List(HistoryFilter{Link: "nightly"}), 2)
+		assert.Len(t, history.List(HistoryFilter{Component: "my-component"}), 4)
+		assert.Empty(t, history.List(HistoryFilter{Component: "other-component"}))
+		assert.Len(t, history.List(HistoryFilter{Outcome: runOutcomeFailure}), 1)
+		assert.Len(t, history.List(HistoryFilter{Since: start.Add(time.Minute)}), 3)
+
+		records := history.List(HistoryFilter{Limit: 2})
+		assert.Len(t, records, 2)
+		assert.Equal(t, "nightly", records[0].Link)
+		assert.Equal(t, runOutcomeSkipped, records[0].Outcome)
+
+		assert.NoError(t, history.Delete("nightly.my-component"))
+		assert.Empty(t, history.List(HistoryFilter{Link: "nightly"}))
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ticker", "history.json")
+		history, err := NewRunHistory(path)
+		assert.NoError(t, err)
+		assert.Empty(t, history.List(HistoryFilter{}))
+
+		for i := range 3 {
+			assert.NoError(t, history.Add("default.my-component", 5, record("default", i, runOutcomeSuccess)))
+		}
+
+		// Changes are written in the background
+		_, err = os.Stat(path)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(path)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// History is read back by a new instance, as after a restart
+		history, err = NewRunHistory(path)
+		assert.NoError(t, err)
+
+		records := history.List(HistoryFilter{})
+		assert.Len(t, records, 3)
+		assert.True(t, start.Add(2*time.Minute).Equal(records[0].StartedAt))
+
+		// The loaded history grows to the size of the task on its next run
+		for i := 3; i < 6; i++ {
+			assert.NoError(t, history.Add("default.my-component", 5, record("default", i, runOutcomeSuccess)))
+		}
+		assert.Len(t, history.List(HistoryFilter{}), 5)
+
+		assert.NoError(t, history.Delete("default.my-component"))
+		assert.NoError(t, history.Close())
+		history, err = NewRunHistory(path)
+		assert.NoError(t, err)
+		assert.Empty(t, history.List(HistoryFilter{}))
+	})
+}
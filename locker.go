@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+var (
+	ErrLockHeld     = errors.New("error job lock held by another replica")
+	ErrNoConnection = errors.New("error no nats connection for distributed mode")
+
+	_ gocron.Locker = (*NatsLocker)(nil)
+	_ gocron.Lock   = (*natsLock)(nil)
+
+	invalidKVKeyChars = regexp.MustCompile(`[^-/_=.a-zA-Z0-9]`)
+)
+
+// NatsLocker is a gocron.Locker backed by a NATS JetStream key-value bucket,
+// so only one provider replica runs each scheduled occurrence of a job.
+//
+// Locks are held for at least the minimum hold time, even if the run finishes
+// sooner, to cover small differences in when each replica fires the same
+// occurrence. The bucket TTL releases locks left behind by a replica which
+// exited mid-run.
+type NatsLocker struct {
+	kv      jetstream.KeyValue
+	owner   string
+	minHold time.Duration
+}
+
+type natsLock struct {
+	locker   *NatsLocker
+	key      string
+	revision uint64
+	acquired time.Time
+}
+
+// NewNatsLocker creates the lock bucket if it does not exist, updating its TTL
+// if it does.
+func NewNatsLocker(ctx context.Context, nc *nats.Conn, bucket string, ttl, minHold time.Duration) (*NatsLocker, error) {
+	if nc == nil {
+		return nil, ErrNoConnection
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "ticker-provider job locks",
+		TTL:         ttl,
+		History:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &NatsLocker{
+		kv:      kv,
+		owner:   uuid.NewString(),
+		minHold: minHold,
+	}, nil
+}
+
+func (l *NatsLocker) Lock(ctx context.Context, key string) (gocron.Lock, error) {
+	key = getKVKey(key)
+	revision, err := l.kv.Create(ctx, key, []byte(l.owner))
+	if errors.Is(err, jetstream.ErrKeyExists) {
+		return nil, ErrLockHeld
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &natsLock{
+		locker:   l,
+		key:      key,
+		revision: revision,
+		acquired: time.Now(),
+	}, nil
+}
+
+// Held reports whether the lock of a job is currently held by any replica.
+func (l *NatsLocker) Held(ctx context.Context, key string) (bool, error) {
+	_, err := l.kv.Get(ctx, getKVKey(key))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *natsLock) Unlock(ctx context.Context) error {
+	if remaining := l.locker.minHold - time.Since(l.acquired); remaining > 0 {
+		time.Sleep(remaining)
+	}
+
+	// The job context may already be cancelled if the job was removed, so
+	// release the lock on a context of its own
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	err := l.locker.kv.Delete(ctx, l.key, jetstream.LastRevision(l.revision))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil
+	}
+	return err
+}
+
+// getKVKey maps a job name onto a valid key-value key.
+func getKVKey(name string) string {
+	return invalidKVKeyChars.ReplaceAllString(name, "_")
+}
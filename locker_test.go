@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+)
+
+// runNatsServer starts an embedded NATS server with JetStream enabled and
+// returns a connection to it.
+func runNatsServer(t *testing.T) *nats.Conn {
+	t.Helper()
+
+	s, err := natsserver.NewServer(&natsserver.Options{
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(s.Shutdown)
+
+	nc, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func TestNatsLocker(t *testing.T) {
+	t.Run("lock held", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctx := context.Background()
+
+		first, err := NewNatsLocker(ctx, nc, "test_locks", time.Minute, 0)
+		assert.NoError(t, err)
+		second, err := NewNatsLocker(ctx, nc, "test_locks", time.Minute, 0)
+		assert.NoError(t, err)
+
+		lock, err := first.Lock(ctx, "default.my-component")
+		assert.NoError(t, err)
+
+		_, err = second.Lock(ctx, "default.my-component")
+		assert.ErrorIs(t, err, ErrLockHeld)
+
+		_, err = second.Lock(ctx, "other.my-component")
+		assert.NoError(t, err)
+
+		err = lock.Unlock(ctx)
+		assert.NoError(t, err)
+
+		_, err = second.Lock(ctx, "default.my-component")
+		assert.NoError(t, err)
+	})
+
+	t.Run("min hold", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctx := context.Background()
+
+		locker, err := NewNatsLocker(ctx, nc, "test_locks", time.Minute, 200*time.Millisecond)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		lock, err := locker.Lock(ctx, "default.my-component")
+		assert.NoError(t, err)
+
+		err = lock.Unlock(ctx)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("expired lock", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ctx := context.Background()
+
+		locker, err := NewNatsLocker(ctx, nc, "test_locks", time.Second, 0)
+		assert.NoError(t, err)
+
+		_, err = locker.Lock(ctx, "default.my-component")
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			_, err := locker.Lock(ctx, "default.my-component")
+			return err == nil
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("no connection", func(t *testing.T) {
+		_, err := NewNatsLocker(context.Background(), nil, "test_locks", time.Minute, 0)
+		assert.ErrorIs(t, err, ErrNoConnection)
+	})
+}
+
+func TestDistributedLockScheduler(t *testing.T) {
+	nc := runNatsServer(t)
+	ctx := context.Background()
+
+	var runs atomic.Int64
+	schedulers := []gocron.Scheduler{}
+	for range 3 {
+		locker, err := NewNatsLocker(ctx, nc, "test_locks", time.Minute, 100*time.Millisecond)
+		assert.NoError(t, err)
+
+		s, err := gocron.NewScheduler(gocron.WithDistributedLocker(locker))
+		assert.NoError(t, err)
+
+		_, err = s.NewJob(
+			gocron.DurationJob(250*time.Millisecond),
+			gocron.NewTask(func() { runs.Add(1) }),
+			gocron.WithName("default.my-component"),
+		)
+		assert.NoError(t, err)
+		schedulers = append(schedulers, s)
+	}
+
+	for _, s := range schedulers {
+		s.Start()
+	}
+	time.Sleep(1100 * time.Millisecond)
+	for _, s := range schedulers {
+		assert.NoError(t, s.Shutdown())
+	}
+
+	// Each occurrence should run on exactly one of the replicas
+	assert.InDelta(t, 4, runs.Load(), 1)
+}
+
+func TestGetKVKey(t *testing.T) {
+	assert.Equal(t, "default.my-component", getKVKey("default.my-component"))
+	assert.Equal(t, "default.my_component_", getKVKey("default.my component*"))
+}
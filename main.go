@@ -7,12 +7,13 @@ import (
 	"os/signal"
 	"syscall"
 
+	server "github.com/jamesstocktonj1/ticker-provider/bindings"
 	slogmulti "github.com/samber/slog-multi"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.wasmcloud.dev/provider"
 )
 
-//go:generate wit-bindgen-wrpc go --out-dir bindings --world imports --package github.com/jamesstocktonj1/ticker-provider/bindings wit
+//go:generate wit-bindgen-wrpc go --out-dir bindings --world provider --package github.com/jamesstocktonj1/ticker-provider/bindings wit
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err)
@@ -46,6 +47,25 @@ func run() error {
 	}
 	t.provider = p
 
+	// Apply provider config from the host
+	if err := t.Configure(p.HostData().Config, p.NatsConnection()); err != nil {
+		p.Shutdown()
+		return err
+	}
+
+	// Answer operator requests on the provider's control subjects
+	if err := t.ServeControl(p.NatsConnection(), p.HostData().LatticeRPCPrefix, p.HostData().ProviderKey, p.HostData().HostID); err != nil {
+		p.Shutdown()
+		return err
+	}
+
+	// Serve the scheduler interface to linked components
+	stopFunc, err := server.Serve(p.RPCClient, t)
+	if err != nil {
+		p.Shutdown()
+		return err
+	}
+
 	// Setup two channels to await RPC and control interface operations
 	providerCh := make(chan error, 1)
 	signalCh := make(chan os.Signal, 1)
@@ -65,9 +85,11 @@ func run() error {
 	// Run provider until either a shutdown is requested or a SIGINT is received
 	select {
 	case err = <-providerCh:
+		stopFunc()
 		t.Shutdown()
 		return err
 	case <-signalCh:
+		stopFunc()
 		t.Shutdown()
 		p.Shutdown()
 	}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	skipReasonOverlap  = "overlap"
+	skipReasonBlackout = "blackout"
+	skipReasonPaused   = "paused"
+)
+
+// Metrics are recorded against the global meter provider, which exports
+// them over OTLP when the host enables metrics in its observability config.
+var (
+	meter = otel.Meter(OtelName)
+
+	// defaultMeterProvider is the global meter provider before the host or
+	// the provider installs one
+	defaultMeterProvider = otel.GetMeterProvider()
+
+	taskRuns, _ = meter.Int64Counter(
+		"ticker.task.runs",
+		metric.WithDescription("Runs of a task, including catch-up runs"),
+		metric.WithUnit("{run}"),
+	)
+	taskSuccesses, _ = meter.Int64Counter(
+		"ticker.task.successes",
+		metric.WithDescription("Runs of a task which succeeded, after any retries"),
+		metric.WithUnit("{run}"),
+	)
+	componentErrors, _ = meter.Int64Counter(
+		"ticker.task.component_errors",
+		metric.WithDescription("Attempts of a task which the component returned an error for"),
+		metric.WithUnit("{attempt}"),
+	)
+	transportErrors, _ = meter.Int64Counter(
+		"ticker.task.transport_errors",
+		metric.WithDescription("Attempts of a task which failed to reach the component or timed out"),
+		metric.WithUnit("{attempt}"),
+	)
+	skippedRuns, _ = meter.Int64Counter(
+		"ticker.task.skipped",
+		metric.WithDescription("Runs of a task which were skipped, by reason"),
+		metric.WithUnit("{run}"),
+	)
+	invocationDuration, _ = meter.Float64Histogram(
+		"ticker.task.duration",
+		metric.WithDescription("Time taken for the component to handle an attempt of a task"),
+		metric.WithUnit("s"),
+	)
+	registeredJobs, _ = meter.Int64ObservableGauge(
+		"ticker.jobs.registered",
+		metric.WithDescription("Jobs registered with the scheduler"),
+		metric.WithUnit("{job}"),
+	)
+	nextRunLag, _ = meter.Float64ObservableGauge(
+		"ticker.task.next_run.lag",
+		metric.WithDescription("Time by which the next run of a task is overdue, zero if it is not yet due"),
+		metric.WithUnit("s"),
+	)
+)
+
+// taskAttributes labels the metrics of a task by its component, link name and
+// schedule type.
+func taskAttributes(task *TickerTask, extra ...attribute.KeyValue) metric.MeasurementOption {
+	return metric.WithAttributes(append([]attribute.KeyValue{
+		attribute.String("component", task.Component),
+		attribute.String("link", task.Link),
+		attribute.String("type", task.Type),
+	}, extra...)...)
+}
+
+// registerMetrics reports the registered jobs and next run lag of the
+// ticker's tasks whenever metrics are collected.
+func (t *Ticker) registerMetrics() (metric.Registration, error) {
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		now := time.Now()
+
+		t.lock.RLock()
+		tasks := make([]*TickerTask, 0, len(t.taskList))
+		for _, task := range t.taskList {
+			tasks = append(tasks, task)
+		}
+		t.lock.RUnlock()
+
+		type labels struct{ component, link, kind string }
+		jobs := map[labels]int64{}
+		lags := map[labels]float64{}
+		for _, task := range tasks {
+			nextRun, ok := task.NextRun()
+			if !ok {
+				continue
+			}
+
+			key := labels{task.Component, task.Link, task.Type}
+			jobs[key]++
+			lags[key] = max(lags[key], now.Sub(nextRun).Seconds())
+		}
+
+		for key, count := range jobs {
+			attrs := metric.WithAttributes(
+				attribute.String("component", key.component),
+				attribute.String("link", key.link),
+				attribute.String("type", key.kind),
+			)
+			o.ObserveInt64(registeredJobs, count, attrs)
+			o.ObserveFloat64(nextRunLag, lags[key], attrs)
+		}
+		return nil
+	}, registeredJobs, nextRunLag)
+}
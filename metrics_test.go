@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.wasmcloud.dev/provider"
+)
+
+var (
+	metricReader     *sdkmetric.ManualReader
+	metricRegistry   *prometheus.Registry
+	metricReaderOnce sync.Once
+)
+
+// installMeterProvider installs a meter provider for the package meter,
+// which can only be done once, read by both a manual reader and Prometheus.
+func installMeterProvider(t *testing.T) {
+	metricReaderOnce.Do(func() {
+		metricReader = sdkmetric.NewManualReader()
+		metricRegistry = prometheus.NewRegistry()
+		exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(metricRegistry))
+		assert.NoError(t, err)
+
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(metricReader),
+			sdkmetric.WithReader(exporter),
+		))
+	})
+}
+
+// collectMetrics collects the current metrics of the package meter.
+func collectMetrics(t *testing.T) map[string]metricdata.Aggregation {
+	installMeterProvider(t)
+
+	var rm metricdata.ResourceMetrics
+	err := metricReader.Collect(context.Background(), &rm)
+	assert.NoError(t, err)
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func componentAttribute(attrs attribute.Set) string {
+	value, _ := attrs.Value("component")
+	return value.AsString()
+}
+
+func TestMetrics(t *testing.T) {
+	collectMetrics(t)
+
+	t.Run("skipped runs", func(t *testing.T) {
+		windows, err := parseBlackouts("0 * * * * for 1h", nil)
+		assert.NoError(t, err)
+
+		ticker := &Ticker{
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+		task := &TickerTask{
+			Component: "skipped-component",
+			ID:        uuid.New(),
+			Link:      "default",
+			Type:      "cron",
+			Blackouts: windows,
+		}
+
+		err = ticker.TaskFunc(context.Background(), task)
+		assert.NoError(t, err)
+		err = ticker.TaskFunc(context.Background(), task)
+		assert.NoError(t, err)
+
+		sum, ok := collectMetrics(t)["ticker.task.skipped"].(metricdata.Sum[int64])
+		assert.True(t, ok)
+		found := false
+		for _, point := range sum.DataPoints {
+			if componentAttribute(point.Attributes) != "skipped-component" {
+				continue
+			}
+			found = true
+			assert.Equal(t, int64(2), point.Value)
+
+			link, _ := point.Attributes.Value("link")
+			assert.Equal(t, "default", link.AsString())
+			kind, _ := point.Attributes.Value("type")
+			assert.Equal(t, "cron", kind.AsString())
+			reason, _ := point.Attributes.Value("reason")
+			assert.Equal(t, skipReasonBlackout, reason.AsString())
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("registered jobs", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "registered-component",
+			TargetConfig: map[string]string{
+				"schedule.fast.period": "1h",
+				"schedule.slow.period": "2h",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		metrics := collectMetrics(t)
+		jobs, ok := metrics["ticker.jobs.registered"].(metricdata.Gauge[int64])
+		assert.True(t, ok)
+		found := false
+		for _, point := range jobs.DataPoints {
+			if componentAttribute(point.Attributes) == "registered-component" {
+				found = true
+				assert.Equal(t, int64(2), point.Value)
+			}
+		}
+		assert.True(t, found)
+
+		lag, ok := metrics["ticker.task.next_run.lag"].(metricdata.Gauge[float64])
+		assert.True(t, ok)
+		for _, point := range lag.DataPoints {
+			if componentAttribute(point.Attributes) == "registered-component" {
+				assert.Equal(t, 0.0, point.Value)
+			}
+		}
+
+		// Jobs of a ticker which has shut down are no longer reported
+		err = ticker.Shutdown()
+		assert.NoError(t, err)
+		jobs, _ = collectMetrics(t)["ticker.jobs.registered"].(metricdata.Gauge[int64])
+		for _, point := range jobs.DataPoints {
+			assert.NotEqual(t, "registered-component", componentAttribute(point.Attributes))
+		}
+	})
+}
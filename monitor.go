@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+)
+
+var _ gocron.Monitor = (*Ticker)(nil)
+
+// IncrementJob is called by the scheduler with the outcome of each job run,
+// including runs which never reach TaskFunc.
+func (t *Ticker) IncrementJob(id uuid.UUID, _ string, _ []string, status gocron.JobStatus) {
+	if status != gocron.SingletonRescheduled {
+		return
+	}
+
+	task, ok := t.getTaskByID(id)
+	if !ok {
+		return
+	}
+
+	// The run is rescheduled as it falls due, and never reaches the listener
+	// which records its scheduled time
+	t.skipTask(context.Background(), task, time.Now(), skipReasonOverlap)
+}
+
+func (t *Ticker) RecordJobTiming(_, _ time.Time, _ uuid.UUID, _ string, _ []string) {}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.wasmcloud.dev/provider"
+)
+
+func TestIncrementJob(t *testing.T) {
+	jobID := uuid.New()
+	task := &TickerTask{
+		Component: "my-component",
+		ID:        jobID,
+		Link:      "default",
+		Overlap:   "skip",
+	}
+
+	ticker := Ticker{
+		taskList: map[string]*TickerTask{
+			"default.my-component": task,
+		},
+		provider: &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		},
+	}
+
+	ticker.IncrementJob(jobID, "", nil, gocron.Success)
+	assert.Equal(t, uint64(0), task.skipped)
+
+	ticker.IncrementJob(jobID, "", nil, gocron.SingletonRescheduled)
+	ticker.IncrementJob(jobID, "", nil, gocron.SingletonRescheduled)
+	assert.Equal(t, uint64(2), task.skipped)
+
+	ticker.IncrementJob(uuid.New(), "", nil, gocron.SingletonRescheduled)
+	assert.Equal(t, uint64(2), task.skipped)
+}
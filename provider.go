@@ -4,13 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
 	"github.com/jamesstocktonj1/ticker-provider/bindings/jamesstocktonj1/ticker/ticker"
+	legacyticker "github.com/jamesstocktonj1/ticker-provider/bindings/jamesstocktonj1/ticker/v0_1_0/ticker"
+	"github.com/nats-io/nats.go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.wasmcloud.dev/provider"
+	wrpc "wrpc.io/go"
 )
 
 const (
@@ -21,126 +35,1621 @@ var (
 	tracer = otel.Tracer(OtelName)
 
 	ErrTickerNotFound = errors.New("error ticker task not found")
+	ErrTaskError      = errors.New("error ticker task failed")
+	ErrTaskTimeout    = errors.New("error ticker task timed out")
+
+	// delayedRunThreshold is how late a run may fire before it is reported
+	// as delayed, e.g. when queued behind a previous run of the same task.
+	delayedRunThreshold = time.Second
+
+	// inFlightPollInterval is how often a run in progress is checked while
+	// waiting for it to finish before its job is replaced.
+	inFlightPollInterval = 10 * time.Millisecond
 )
 
 type Ticker struct {
 	provider *provider.WasmcloudProvider
+	metrics  metric.Registration
+	server   *http.Server
+	control  []*nats.Subscription
+	invoker  func(component string) wrpc.Invoker
 	tasks    gocron.Scheduler
 	taskList map[string]*TickerTask
+	lock     sync.RWMutex
+	links    sync.Mutex
+
+	defaultTimeout time.Duration
+	elector        *NatsElector
+	locker         *NatsLocker
+	state          StateStore
+	blackouts      []*BlackoutWindow
+	history        *RunHistory
+
+	// Tasks are unhealthy after failing this many runs in a row, or going
+	// longer than the maximum age without a successful run
+	failureThreshold uint64
+	maxSuccessAge    time.Duration
+
+	// Jobs scheduled by components at runtime only exist on the replica
+	// which received the call, so in leader mode they run on a scheduler
+	// without the elector
+	runtimeTasks gocron.Scheduler
+
+	// Calendar jobs in a time zone other than the provider default run on
+	// a scheduler of their own, keyed by location name
+	location *time.Location
+	options  []gocron.SchedulerOption
+	zones    map[string]gocron.Scheduler
+	started  bool
 }
 
 type TickerTask struct {
 	Component string
 	ID        uuid.UUID
+	Link      string
+	Schedule  string
 	Type      string
+	Version   string
+	Config    map[string]string
+	Retry     *RetryPolicy
+	Timeout   time.Duration
+	Overlap   string
+	Location  *time.Location
+	Jitter    time.Duration
+	StartAt   time.Time
+	EndAt     time.Time
+	MaxRuns   uint64
+	Blackouts []*BlackoutWindow
+	Critical  bool
+	// HistorySize is the number of runs kept in the task's run history
+	HistorySize int
+
+	key           string
+	runtime       bool
+	job           gocron.Job
+	endTimer      *time.Timer
+	options       []gocron.JobOption
+	next          func(time.Time) time.Time
+	runs          []time.Time
+	refreshTimer  *time.Timer
+	inFlight      atomic.Int32
+	mu            sync.Mutex
+	runCount      uint64
+	skipped       uint64
+	delayed       uint64
+	failures      uint64
+	paused        bool
+	nextRun       time.Time
+	lastScheduled time.Time
+	lastSuccess   time.Time
+	lastFailure   time.Time
+}
+
+// invocation builds the context passed to the component for a run fired at
+// the given time and increments the task's run counter.
+func (t *TickerTask) invocation(firedAt time.Time) (*ticker.InvocationContext, bool) {
+	return t.invocationAt(t.scheduledAt(firedAt), firedAt)
+}
+
+// scheduledAt returns the time the run fired at the given time was scheduled
+// for, which is the fired time if the task has no next run recorded.
+func (t *TickerTask) scheduledAt(firedAt time.Time) time.Time {
+	t.mu.Lock()
+	scheduledAt := t.nextRun
+	t.mu.Unlock()
+
+	if scheduledAt.IsZero() || scheduledAt.After(firedAt) {
+		return firedAt
+	}
+	return scheduledAt
+}
+
+// invocationAt builds the context for a run which was scheduled for the
+// given time, such as a catch-up for a missed run. The run is reserved along
+// with the check of the task's max runs, so runs fired together cannot
+// exceed them, and false is returned once they are used up.
+func (t *TickerTask) invocationAt(scheduledAt, firedAt time.Time) (*ticker.InvocationContext, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.MaxRuns > 0 && t.runCount >= t.MaxRuns {
+		return nil, false
+	}
+
+	t.runCount++
+	if scheduledAt.After(t.lastScheduled) {
+		t.lastScheduled = scheduledAt
+	}
+
+	return &ticker.InvocationContext{
+		JobId:        t.ID.String(),
+		LinkName:     t.Link,
+		ScheduleType: t.Type,
+		ScheduledAt:  uint64(scheduledAt.UnixMilli()),
+		FiredAt:      uint64(firedAt.UnixMilli()),
+		RunCount:     t.runCount,
+		Attempt:      1,
+	}, true
+}
+
+// release gives back a run reserved by an invocation which was not fired.
+func (t *TickerTask) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runCount > 0 {
+		t.runCount--
+	}
+}
+
+// updateNextRun records when the scheduler will next fire the task, so the
+// following invocation can report its scheduled time.
+// Before a run, the scheduler still reports the run about to fire.
+func (t *TickerTask) updateNextRun() {
+	nextRun, ok := t.NextRun()
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextRun = nextRun
+}
+
+// nextRunListener records the scheduled time of each run as it fires, so
+// runs which are skipped report their own time rather than an earlier run's.
+func (t *TickerTask) nextRunListener() gocron.JobOption {
+	return gocron.WithEventListeners(
+		gocron.BeforeJobRuns(func(uuid.UUID, string) { t.updateNextRun() }),
+	)
+}
+
+// NextRun returns the time the scheduler will next fire the task.
+func (t *TickerTask) NextRun() (time.Time, bool) {
+	t.mu.Lock()
+	job := t.job
+	t.mu.Unlock()
+	if job == nil {
+		return time.Time{}, false
+	}
+
+	nextRun, err := job.NextRun()
+	if err != nil || nextRun.IsZero() {
+		return time.Time{}, false
+	}
+	return nextRun, true
+}
+
+// complete records the outcome of a run which finished at the given time.
+func (t *TickerTask) complete(at time.Time, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		t.lastFailure = at
+		t.failures++
+	} else {
+		t.lastSuccess = at
+		t.failures = 0
+	}
+}
+
+// unhealthy returns why a task is unhealthy, if it has failed too many runs
+// in a row or has not succeeded for too long.
+func (t *TickerTask) unhealthy(now time.Time, threshold uint64, maxAge time.Duration) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if threshold > 0 && t.failures >= threshold {
+		return fmt.Sprintf("%s (%d consecutive failures)", t.key, t.failures), true
+	}
+	if maxAge > 0 && !t.lastSuccess.IsZero() && now.Sub(t.lastSuccess) > maxAge {
+		return fmt.Sprintf("%s (last success %s ago)", t.key, now.Sub(t.lastSuccess).Round(time.Second)), true
+	}
+	return "", false
+}
+
+// State returns the state of the task which is kept across restarts.
+func (t *TickerTask) State() TaskState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return TaskState{
+		LastScheduled: t.lastScheduled,
+		LastSuccess:   t.lastSuccess,
+		LastFailure:   t.lastFailure,
+		RunCount:      t.runCount,
+		Paused:        t.paused,
+		ConfigHash:    getConfigHash(t.Config),
+	}
+}
+
+// restore picks up the state of the task from before a restart.
+func (t *TickerTask) restore(state TaskState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastScheduled = state.LastScheduled
+	t.lastSuccess = state.LastSuccess
+	t.lastFailure = state.LastFailure
+	t.runCount = state.RunCount
+	t.paused = state.Paused
+}
+
+// Paused reports whether the runs of the task are being skipped until it is
+// resumed.
+func (t *TickerTask) Paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paused
+}
+
+// setPaused pauses or resumes the task, reporting whether it changed.
+func (t *TickerTask) setPaused(paused bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	changed := t.paused != paused
+	t.paused = paused
+	return changed
+}
+
+// finished reports whether the task has reached the end of its active window
+// or used up its runs.
+func (t *TickerTask) finished(now time.Time) bool {
+	if !t.EndAt.IsZero() && !t.EndAt.After(now) {
+		return true
+	}
+	return t.MaxRuns > 0 && t.State().RunCount >= t.MaxRuns
+}
+
+// windowOptions limits the job of the task to its active window. Its runs
+// are counted by the task rather than the scheduler, which would also count
+// runs skipped by a blackout or pause.
+func (t *TickerTask) windowOptions(now time.Time) []gocron.JobOption {
+	options := []gocron.JobOption{}
+	// Computed runs already start from the start of the window
+	if t.StartAt.After(now) && t.next == nil {
+		options = append(options, gocron.WithStartAt(gocron.WithStartDateTime(t.StartAt)))
+	}
+	if !t.EndAt.IsZero() {
+		options = append(options, gocron.WithStopAt(gocron.WithStopDateTime(t.EndAt)))
+	}
+	return options
+}
+
+// computedRuns returns the runs of a task with a schedule computed by the
+// provider which fall after the given time and within its active window.
+func (t *TickerTask) computedRuns(now time.Time) []time.Time {
+	from := now
+	if t.StartAt.After(now) {
+		from = t.StartAt.Add(-time.Nanosecond)
+	}
+
+	runs := getComputedRuns(t.next, from)
+	if !t.EndAt.IsZero() {
+		runs = slices.DeleteFunc(runs, func(run time.Time) bool { return run.After(t.EndAt) })
+	}
+	return runs
+}
+
+// refreshAtLocked returns when the computed runs of a task should be extended,
+// between two runs half way through the current batch so the refresh does
+// not cancel a run in progress.
+// The task's lock must be held.
+func (t *TickerTask) refreshAtLocked() time.Time {
+	if len(t.runs) == 1 {
+		return t.runs[0].Add(t.Timeout + time.Second)
+	}
+	i := (len(t.runs) - 1) / 2
+	return t.runs[i].Add(t.runs[i+1].Sub(t.runs[i]) / 2)
+}
+
+func (t *TickerTask) stopTimers() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.endTimer != nil {
+		t.endTimer.Stop()
+	}
+	if t.refreshTimer != nil {
+		t.refreshTimer.Stop()
+	}
+}
+
+// setJob records the job of the task along with its first run, so the first
+// invocation reports the time it was scheduled for.
+func (t *TickerTask) setJob(job gocron.Job) {
+	nextRun, _ := job.NextRun()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.job = job
+	t.nextRun = nextRun
+}
+
+// skip records a run which was skipped, returning the number of runs skipped
+// so far.
+func (t *TickerTask) skip() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.skipped++
+	return t.skipped
+}
+
+// delay records a run which fired later than scheduled, returning the number
+// of runs delayed so far.
+func (t *TickerTask) delay() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.delayed++
+	return t.delayed
 }
 
 func CreateTicker() (*Ticker, error) {
-	s, err := gocron.NewScheduler()
+	history, err := NewRunHistory("")
 	if err != nil {
 		return nil, err
 	}
 
-	return &Ticker{
-		tasks:    s,
-		taskList: make(map[string]*TickerTask),
-	}, nil
+	t := &Ticker{
+		taskList:         make(map[string]*TickerTask),
+		history:          history,
+		defaultTimeout:   timeoutDefault,
+		failureThreshold: healthFailuresDefault,
+		location:         time.Local,
+		zones:            make(map[string]gocron.Scheduler),
+	}
+
+	t.tasks, err = t.newScheduler()
+	if err != nil {
+		return nil, err
+	}
+
+	t.metrics, err = t.registerMetrics()
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Ticker) newScheduler(options ...gocron.SchedulerOption) (gocron.Scheduler, error) {
+	return gocron.NewScheduler(append([]gocron.SchedulerOption{
+		gocron.WithMonitor(t),
+	}, options...)...)
+}
+
+// Configure applies the provider-wide config passed in by the host. It must
+// be called before the scheduler is started or any jobs are added.
+func (t *Ticker) Configure(config map[string]string, nc *nats.Conn) error {
+	timeout, err := getTimeout(config, defaultTimeoutConfigKey, timeoutDefault)
+	if err != nil {
+		return err
+	}
+
+	options, err := t.distributedOptions(config, nc)
+	if err != nil {
+		return err
+	}
+
+	lattice, providerID := t.hostScope()
+	state, err := newStateStore(config, nc, lattice, providerID)
+	if err != nil {
+		return err
+	}
+
+	blackouts, err := getBlackouts(config)
+	if err != nil {
+		return err
+	}
+
+	failureThreshold, err := getFailureThreshold(config)
+	if err != nil {
+		return err
+	}
+
+	maxSuccessAge, err := getTimeout(config, healthSuccessAgeConfigKey, 0)
+	if err != nil {
+		return err
+	}
+
+	history := t.history
+	if path, ok := config[historyFileConfigKey]; ok {
+		history, err = NewRunHistory(path)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", historyFileConfigKey, err)
+		}
+	}
+
+	location, err := getLocation(config)
+	if err != nil {
+		return err
+	}
+	localOptions := []gocron.SchedulerOption{}
+	if location != nil {
+		localOptions = append(localOptions, gocron.WithLocation(location))
+		options = append(options, gocron.WithLocation(location))
+		t.location = location
+	}
+	t.options = options
+
+	if t.elector != nil {
+		t.runtimeTasks, err = t.newScheduler(localOptions...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(options) > 0 {
+		s, err := t.newScheduler(options...)
+		if err != nil {
+			return err
+		}
+
+		err = t.tasks.Shutdown()
+		if err != nil {
+			return err
+		}
+		t.tasks = s
+	}
+
+	if address, ok := config[metricsAddressConfigKey]; ok {
+		// Metrics the host exports over OTLP cannot be read again, so the
+		// endpoint is left out rather than failing the provider
+		gatherer, err := newPrometheusGatherer()
+		if errors.Is(err, ErrMetricsExported) {
+			t.provider.Logger.Warn("metrics endpoint disabled", "error", err, "address", address)
+		} else if err != nil {
+			return fmt.Errorf("key %s: %w", metricsAddressConfigKey, err)
+		} else {
+			err = t.serveMetrics(address, gatherer)
+			if err != nil {
+				return fmt.Errorf("key %s: %w", metricsAddressConfigKey, err)
+			}
+		}
+	}
+
+	t.defaultTimeout = timeout
+	t.state = state
+	t.blackouts = blackouts
+	t.failureThreshold = failureThreshold
+	t.maxSuccessAge = maxSuccessAge
+	t.history = history
+	return nil
+}
+
+// distributedOptions returns the scheduler options used to coordinate jobs
+// between replicas of the provider, according to the host config.
+func (t *Ticker) distributedOptions(config map[string]string, nc *nats.Conn) ([]gocron.SchedulerOption, error) {
+	mode, err := getDistributedMode(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case distributedLock:
+		locker, err := newLocker(config, nc)
+		if err != nil {
+			return nil, err
+		}
+		t.locker = locker
+		return []gocron.SchedulerOption{gocron.WithDistributedLocker(locker)}, nil
+	case distributedLeader:
+		elector, err := newElector(config, nc, t.replicaID(), t.logger())
+		if err != nil {
+			return nil, err
+		}
+		t.elector = elector
+		return []gocron.SchedulerOption{gocron.WithDistributedElector(elector)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// replicaID identifies this replica of the provider to the others, using
+// the host it is running on.
+func (t *Ticker) replicaID() string {
+	if t.provider != nil && t.provider.HostData().HostID != "" {
+		return t.provider.HostData().HostID
+	}
+	return uuid.NewString()
+}
+
+// hostScope returns the lattice and ID of this provider instance, which are
+// empty when it is not running under a host.
+func (t *Ticker) hostScope() (string, string) {
+	if t.provider == nil {
+		return "", ""
+	}
+	return t.provider.HostData().LatticeRPCPrefix, t.provider.HostData().ProviderKey
+}
+
+func (t *Ticker) logger() *slog.Logger {
+	if t.provider != nil && t.provider.Logger != nil {
+		return t.provider.Logger
+	}
+	return slog.Default()
 }
 
 func (t *Ticker) Start() error {
 	_, span := tracer.Start(context.Background(), "Start")
 	defer span.End()
 
+	if t.elector != nil {
+		t.elector.Start()
+	}
+
+	t.lock.Lock()
+	t.started = true
+	zones := slices.Collect(maps.Values(t.zones))
+	tasks := slices.Collect(maps.Values(t.taskList))
+	t.lock.Unlock()
+
 	t.tasks.Start()
+	if t.runtimeTasks != nil {
+		t.runtimeTasks.Start()
+	}
+	for _, s := range zones {
+		s.Start()
+	}
+
+	// Jobs put before the scheduler started only have a first run now
+	for _, task := range tasks {
+		task.updateNextRun()
+	}
 	return nil
 }
 
 func (t *Ticker) Shutdown() error {
-	err := t.tasks.Shutdown()
+	if t.metrics != nil {
+		err := t.metrics.Unregister()
+		if err != nil {
+			return err
+		}
+		t.metrics = nil
+	}
+
+	if t.server != nil {
+		err := t.server.Close()
+		if err != nil {
+			return err
+		}
+		t.server = nil
+	}
+
+	err := t.stopControl()
+	if err != nil {
+		return err
+	}
+
+	t.lock.Lock()
+	t.started = false
+	zones := slices.Collect(maps.Values(t.zones))
+	t.lock.Unlock()
+
+	err = t.tasks.Shutdown()
 	if err != nil {
 		return err
 	}
+	if t.runtimeTasks != nil {
+		err = t.runtimeTasks.Shutdown()
+		if err != nil {
+			return err
+		}
+	}
+	for _, s := range zones {
+		err = errors.Join(err, s.Shutdown())
+	}
+	if err != nil {
+		return err
+	}
+
+	if t.history != nil {
+		err = t.history.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if t.state != nil {
+		err = t.state.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.elector != nil {
+		return t.elector.Stop()
+	}
 	return nil
 }
 
-func (t *Ticker) TaskFunc(task *TickerTask) error {
-	ctx, span := tracer.Start(context.Background(), "TaskFunc")
+// runtimeScheduler returns the scheduler for jobs scheduled by components,
+// which is the main scheduler unless it only runs jobs on the leader.
+func (t *Ticker) runtimeScheduler() gocron.Scheduler {
+	if t.runtimeTasks != nil {
+		return t.runtimeTasks
+	}
+	return t.tasks
+}
+
+// getScheduler returns the scheduler for jobs in a location, creating one
+// for locations other than the provider default.
+func (t *Ticker) getScheduler(location *time.Location) (gocron.Scheduler, error) {
+	if location == nil || location.String() == t.location.String() {
+		return t.tasks, nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if s, ok := t.zones[location.String()]; ok {
+		return s, nil
+	}
+
+	s, err := t.newScheduler(append(slices.Clone(t.options), gocron.WithLocation(location))...)
+	if err != nil {
+		return nil, err
+	}
+	if t.started {
+		s.Start()
+	}
+
+	if t.zones == nil {
+		t.zones = make(map[string]gocron.Scheduler)
+	}
+	t.zones[location.String()] = s
+	return s, nil
+}
+
+func (t *Ticker) TaskFunc(ctx context.Context, task *TickerTask) error {
+	task.inFlight.Add(1)
+	defer task.inFlight.Add(-1)
+
+	if task.Paused() {
+		t.skipTask(ctx, task, task.scheduledAt(time.Now()), skipReasonPaused)
+		return nil
+	}
+
+	if window, ok := t.getBlackout(task, time.Now()); ok {
+		t.skipTask(ctx, task, task.scheduledAt(time.Now()), skipReasonBlackout, "blackout", window.String())
+		return nil
+	}
+
+	// Runs still due once the task has used up its runs are dropped, as the
+	// task is only removed once its last run completes
+	invocation, ok := task.invocation(time.Now())
+	if !ok {
+		return nil
+	}
+
+	// Spread out the runs of jobs which are due at the same time, holding
+	// on to the reserved run until it fires
+	if task.Jitter > 0 {
+		select {
+		case <-ctx.Done():
+			task.release()
+			return ctx.Err()
+		case <-time.After(rand.N(task.Jitter)):
+		}
+		invocation.FiredAt = uint64(time.Now().UnixMilli())
+	}
+
+	return t.executeTask(ctx, task, invocation, false)
+}
+
+// CatchUpFunc fires catch-up invocations for runs missed while the provider
+// was down, in the order they were scheduled.
+func (t *Ticker) CatchUpFunc(ctx context.Context, task *TickerTask, missed []time.Time) error {
+	errs := []error{}
+	for _, scheduledAt := range missed {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if task.MaxRuns > 0 && task.finished(time.Now()) {
+			break
+		}
+
+		if task.Paused() {
+			t.skipTask(ctx, task, scheduledAt, skipReasonPaused, "scheduled_at", scheduledAt)
+			continue
+		}
+
+		if window, ok := t.getBlackout(task, scheduledAt); ok {
+			t.skipTask(ctx, task, scheduledAt, skipReasonBlackout, "blackout", window.String(), "scheduled_at", scheduledAt)
+			continue
+		}
+
+		invocation, ok := task.invocationAt(scheduledAt, time.Now())
+		if !ok {
+			break
+		}
+
+		err := t.executeTask(ctx, task, invocation, true)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// getBlackout returns the blackout window a run of the task at the given time
+// falls in, if any. Critical tasks only observe their own windows.
+func (t *Ticker) getBlackout(task *TickerTask, at time.Time) (*BlackoutWindow, bool) {
+	if window, ok := getBlackout(task.Blackouts, at); ok {
+		return window, true
+	}
+	if task.Critical {
+		return nil, false
+	}
+	return getBlackout(t.blackouts, at)
+}
+
+// skipTask records a run of a task which was skipped rather than fired.
+func (t *Ticker) skipTask(ctx context.Context, task *TickerTask, scheduledAt time.Time, reason string, args ...any) {
+	ctx, span := tracer.Start(ctx, "TaskSkipped")
+	defer span.End()
+
 	span.SetAttributes(
 		attribute.String("id", task.ID.String()),
 		attribute.String("component", task.Component),
-		attribute.String("type", task.Type),
+		attribute.String("link", task.Link),
+		attribute.String("schedule", task.Schedule),
+		attribute.String("skip_reason", reason),
 	)
+	skippedRuns.Add(ctx, 1, taskAttributes(task, attribute.String("reason", reason)))
+
+	now := time.Now()
+	t.recordHistory(task, RunRecord{
+		ScheduledAt: scheduledAt,
+		StartedAt:   now,
+		EndedAt:     now,
+		Outcome:     runOutcomeSkipped,
+		Error:       reason,
+		TraceID:     traceID(span),
+	})
+
+	skipped := task.skip()
+	t.provider.Logger.Warn("task skipped", append([]any{"id", task.ID.String(), "component", task.Component, "link", task.Link, "schedule", task.Schedule, "reason", reason, "skipped", skipped}, args...)...)
+}
+
+func (t *Ticker) executeTask(ctx context.Context, task *TickerTask, invocation *ticker.InvocationContext, catchUp bool) (err error) {
+	ctx, span := tracer.Start(ctx, "TaskFunc")
 	defer span.End()
+	defer func() { t.recordResult(task, err) }()
 
-	t.provider.Logger.Info("task execute", "id", task.ID.String(), "component", task.Component, "type", task.Type)
+	startedAt := time.Now()
+	defer func() {
+		record := RunRecord{
+			ScheduledAt: time.UnixMilli(int64(invocation.ScheduledAt)),
+			StartedAt:   startedAt,
+			EndedAt:     time.Now(),
+			Attempts:    invocation.Attempt,
+			Outcome:     runOutcomeSuccess,
+			TraceID:     traceID(span),
+			CatchUp:     catchUp,
+		}
+		if err != nil {
+			record.Outcome = runOutcomeFailure
+			record.Error = err.Error()
+		}
+		t.recordHistory(task, record)
+	}()
 
-	taskErr, err := ticker.Task(
-		injectTraceHeader(ctx),
-		t.provider.OutgoingRpcClient(task.Component),
+	span.SetAttributes(
+		attribute.String("id", task.ID.String()),
+		attribute.String("component", task.Component),
+		attribute.String("link", task.Link),
+		attribute.String("schedule", task.Schedule),
+		attribute.String("type", task.Type),
+		attribute.String("version", task.Version),
+		attribute.Int64("run_count", int64(invocation.RunCount)),
+		attribute.Bool("catch_up", catchUp),
 	)
-	if err != nil || taskErr == nil {
-		t.provider.Logger.Error("error: ticker.Task", "error", err, "id", task.ID.String())
+
+	t.provider.Logger.Info("task execute", "id", task.ID.String(), "component", task.Component, "link", task.Link, "schedule", task.Schedule, "type", task.Type, "run_count", invocation.RunCount, "catch_up", catchUp)
+
+	taskRuns.Add(ctx, 1, taskAttributes(task))
+
+	lag := time.Duration(invocation.FiredAt-invocation.ScheduledAt) * time.Millisecond
+	if lag > delayedRunThreshold+task.Jitter && !catchUp {
+		delayed := task.delay()
+		t.provider.Logger.Warn("task delayed", "id", task.ID.String(), "component", task.Component, "link", task.Link, "lag", lag, "delayed", delayed)
+		span.SetAttributes(attribute.Int64("lag_ms", lag.Milliseconds()))
+	}
+
+	for {
+		err = t.attemptTask(ctx, task, invocation)
+		if err == nil {
+			taskSuccesses.Add(ctx, 1, taskAttributes(task))
+			return nil
+		}
+
+		if !task.Retry.ShouldRetry(invocation.Attempt, err) {
+			t.provider.Logger.Error("error: task failed", "error", err, "id", task.ID.String(), "attempts", invocation.Attempt)
+			span.RecordError(err)
+			return err
+		}
+
+		delay := task.Retry.Delay(invocation.Attempt)
+		t.provider.Logger.Warn("task retry", "id", task.ID.String(), "attempt", invocation.Attempt, "delay", delay)
+		select {
+		case <-ctx.Done():
+			span.RecordError(ctx.Err())
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		invocation.Attempt++
+	}
+}
+
+// recordResult records the outcome of a run, persisting the state of link
+// jobs so it survives a restart.
+func (t *Ticker) recordResult(task *TickerTask, err error) {
+	task.complete(time.Now(), err)
+	t.saveState(task)
+
+	if task.MaxRuns > 0 && task.finished(time.Now()) {
+		t.finishTask(task)
+	}
+}
+
+// recordHistory adds a run to the history of its task, filling in the
+// details of the task.
+func (t *Ticker) recordHistory(task *TickerTask, record RunRecord) {
+	if t.history == nil {
+		return
+	}
+
+	record.Key = task.key
+	record.Component = task.Component
+	record.Link = task.Link
+	record.Schedule = task.Schedule
+	record.Type = task.Type
+
+	err := t.history.Add(task.key, task.HistorySize, record)
+	if err != nil {
+		t.provider.Logger.Error("error: history.Add", "error", err, "id", task.ID.String(), "link", task.Link)
+	}
+}
+
+func (t *Ticker) saveState(task *TickerTask) {
+	if t.state == nil || task.runtime {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := t.state.Save(ctx, task.key, task.State())
+	if err != nil {
+		t.provider.Logger.Error("error: state.Save", "error", err, "id", task.ID.String(), "link", task.Link)
+	}
+}
+
+// loadState restores the state of a link job from before a restart.
+func (t *Ticker) loadState(task *TickerTask) {
+	if t.state == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, ok, err := t.state.Load(ctx, task.key)
+	if err != nil {
+		t.provider.Logger.Error("error: state.Load", "error", err, "link", task.Link, "component", task.Component)
+		return
+	} else if !ok {
+		return
+	}
+
+	task.restore(state)
+	t.provider.Logger.Info("task state restored", "link", task.Link, "component", task.Component, "run_count", state.RunCount, "last_scheduled", state.LastScheduled)
+}
+
+// isUnchanged reports whether a schedule has been put before with the same
+// config, either since the provider started or before it restarted. State
+// saved before configs were recorded is taken to have the same config.
+func (t *Ticker) isUnchanged(key string, config map[string]string) bool {
+	t.lock.RLock()
+	task, ok := t.taskList[key]
+	t.lock.RUnlock()
+	if ok {
+		return len(getChangedKeys(task.Config, config)) == 0
+	} else if t.state == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state, ok, err := t.state.Load(ctx, key)
+	if err != nil || !ok {
+		return false
+	}
+	return state.ConfigHash == "" || state.ConfigHash == getConfigHash(config)
+}
+
+// deleteState forgets the state of a link job once its link is removed.
+func (t *Ticker) deleteState(jobKey string) {
+	if t.state == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := t.state.Delete(ctx, jobKey)
+	if err != nil {
+		t.provider.Logger.Error("error: state.Delete", "error", err, "key", jobKey)
+	}
+}
+
+// resizeHistory applies the history size of a task put by a link, which
+// also trims any history loaded from before a restart.
+func (t *Ticker) resizeHistory(task *TickerTask) {
+	if t.history == nil {
+		return
+	}
+
+	err := t.history.Resize(task.key, task.HistorySize)
+	if err != nil {
+		t.provider.Logger.Error("error: history.Resize", "error", err, "link", task.Link, "component", task.Component)
+	}
+}
+
+// deleteHistory forgets the run history of a task once it is removed.
+func (t *Ticker) deleteHistory(jobKey string) {
+	if t.history == nil {
+		return
+	}
+
+	err := t.history.Delete(jobKey)
+	if err != nil {
+		t.provider.Logger.Error("error: history.Delete", "error", err, "key", jobKey)
+	}
+}
+
+// attemptTask makes a single invocation of the component's task, returning
+// ErrTaskError if the component reported a failure or ErrTaskTimeout if the
+// invocation did not complete within the task's timeout.
+func (t *Ticker) attemptTask(ctx context.Context, task *TickerTask, invocation *ticker.InvocationContext) error {
+	ctx, span := tracer.Start(ctx, "TaskAttempt")
+	span.SetAttributes(
+		attribute.String("id", task.ID.String()),
+		attribute.Int64("attempt", int64(invocation.Attempt)),
+	)
+	defer span.End()
+
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	taskErr, err := t.invokeTask(injectTraceHeader(ctx), task, invocation)
+	invocationDuration.Record(ctx, time.Since(start).Seconds(), taskAttributes(task))
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err := fmt.Errorf("%w after %s: %w", ErrTaskTimeout, task.Timeout, err)
+		t.provider.Logger.Error("error: ticker.Task timeout", "error", err, "id", task.ID.String(), "attempt", invocation.Attempt, "timeout", task.Timeout)
+		span.RecordError(err)
+		transportErrors.Add(ctx, 1, taskAttributes(task, attribute.Bool("timeout", true)))
+		return err
+	} else if err != nil || taskErr == nil {
+		t.provider.Logger.Error("error: ticker.Task", "error", err, "id", task.ID.String(), "attempt", invocation.Attempt)
 		span.RecordError(err)
+		transportErrors.Add(ctx, 1, taskAttributes(task, attribute.Bool("timeout", false)))
 		return err
-	} else if taskErr.Discriminant() != ticker.TaskErrorNone {
-		err := errors.New(taskErr.String())
-		t.provider.Logger.Error("error: ticker.Task TaskError", "error", err, "id", task.ID.String())
+	} else if payload, ok := taskErr.GetError(); ok {
+		err := fmt.Errorf("%w: %s", ErrTaskError, payload)
+		t.provider.Logger.Error("error: ticker.Task TaskError", "error", err, "id", task.ID.String(), "attempt", invocation.Attempt)
 		span.RecordError(err)
+		componentErrors.Add(ctx, 1, taskAttributes(task))
 		return err
 	}
 
 	return nil
 }
 
+// invokeTask calls the task function exported by the component, using the
+// interface version configured on the link.
+func (t *Ticker) invokeTask(ctx context.Context, task *TickerTask, invocation *ticker.InvocationContext) (*ticker.TaskError, error) {
+	client := t.rpcClient(task.Component)
+	if task.Version != tickerVersionLegacy {
+		return ticker.Task(ctx, client, invocation)
+	}
+
+	taskErr, err := legacyticker.Task(ctx, client)
+	if err != nil || taskErr == nil {
+		return nil, err
+	}
+	if payload, ok := taskErr.GetError(); ok {
+		return ticker.NewTaskErrorError(payload), nil
+	}
+	return ticker.NewTaskErrorNone(), nil
+}
+
+// rpcClient returns the client used to invoke a component, which tests may
+// replace with a fake.
+func (t *Ticker) rpcClient(component string) wrpc.Invoker {
+	if t.invoker != nil {
+		return t.invoker(component)
+	}
+	return t.provider.OutgoingRpcClient(component)
+}
+
+func (t *Ticker) getTaskByID(id uuid.UUID) (*TickerTask, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for _, task := range t.taskList {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return nil, false
+}
+
 func (t *Ticker) handlePutTargetLink(link provider.InterfaceLinkDefinition) error {
 	t.provider.Logger.Info("handlePutTargetLink", "link", link)
 
-	jobDef, err := newSchedulerJob(link.TargetConfig)
+	t.links.Lock()
+	defer t.links.Unlock()
+
+	configs, err := getLinkSchedules(link.TargetConfig)
 	if err != nil {
 		return err
 	}
 
-	jobKey := getJobKey(link)
-	jobCtx := &TickerTask{
-		Component: link.SourceID,
-		Type:      link.TargetConfig[configTypeKey],
+	// Parse every schedule before applying any, so an invalid link config
+	// leaves the existing jobs untouched
+	schedules := map[string]*linkSchedule{}
+	for name, config := range configs {
+		schedule, err := t.newLinkSchedule(link, name, config)
+		if err != nil && name != "" {
+			return fmt.Errorf("schedule %s: %w", name, err)
+		} else if err != nil {
+			return err
+		}
+		schedules[schedule.task.key] = schedule
+	}
+
+	for key, task := range t.getLinkTasks(link) {
+		if _, ok := schedules[key]; ok {
+			continue
+		}
+
+		t.provider.Logger.Info("link schedule removed", "link", link.Name, "component", link.SourceID, "schedule", task.Schedule)
+		err := t.removeTask(key, task)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(schedules)) {
+		err := t.putSchedule(schedules[key])
+		if err != nil && schedules[key].task.Schedule != "" {
+			return fmt.Errorf("schedule %s: %w", schedules[key].task.Schedule, err)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkSchedule is a parsed schedule of a link, ready to be applied.
+type linkSchedule struct {
+	task    *TickerTask
+	jobDef  gocron.JobDefinition
+	options []gocron.JobOption
+}
+
+func (t *Ticker) newLinkSchedule(link provider.InterfaceLinkDefinition, name string, config map[string]string) (*linkSchedule, error) {
+	// Schedules without a time zone run in the provider default location
+	if _, ok := getTimezone(config); !ok && t.location != nil && t.location != time.Local {
+		config[timezoneConfigKey] = t.location.String()
+	}
+
+	jobKey := getScheduleKey(link, name)
+	jobDef, err := newSchedulerJob(config)
+	if errors.Is(err, ErrTimeInPast) && t.isUnchanged(jobKey, config) {
+		// Times of a one-time job which passed after the link was put, e.g.
+		// over a provider restart, are skipped rather than rejected unless
+		// the link has changed since
+		jobDef, err = nil, nil
+		if config[configTypeKey] == configTypeOnce {
+			jobDef, err = newRemainingOnceJob(config)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := getJobLocation(config)
+	if err != nil {
+		return nil, err
+	}
+
+	jobOptions, err := newSchedulerOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	// Job names are shared between replicas and used as the lock key
+	jobOptions = append(jobOptions, gocron.WithName(jobKey))
+
+	version, err := getTaskVersion(config)
+	if err != nil {
+		return nil, err
+	}
+
+	retry, err := newRetryPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout, err := getTimeout(config, timeoutConfigKey, t.defaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = getMisfireLimit(config)
+	if err != nil {
+		return nil, err
+	}
+
+	jitter, err := getJitter(config)
+	if err != nil {
+		return nil, err
+	}
+
+	blackouts, err := getBlackouts(config)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt, endAt, err := getActiveWindow(config)
+	if err != nil {
+		return nil, err
+	}
+	if !endAt.IsZero() && endAt.Before(time.Now()) && !t.isUnchanged(jobKey, config) {
+		return nil, fmt.Errorf("%w: key %s: %s: %w", ErrInvalidConfigValue, endAtConfigKey, config[endAtConfigKey], ErrTimeInPast)
+	}
+
+	maxRuns, err := getMaxRuns(config)
+	if err != nil {
+		return nil, err
+	}
+
+	historySize, err := getHistorySize(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Runs of recurrence rules and jobs with a holiday calendar are computed
+	// by the provider, as the scheduler has no way to express them
+	var next func(time.Time) time.Time
+	if isComputedSchedule(config) {
+		anchor := startAt
+		if anchor.IsZero() {
+			anchor = time.Now()
+		}
+		next, err = newScheduleFunc(config, anchor)
+		if err != nil {
+			return nil, err
+		} else if next == nil {
+			return nil, fmt.Errorf("%w: key %s: not supported by type %s", ErrInvalidConfigValue, calendarConfigKey, config[configTypeKey])
+		}
+	}
+
+	return &linkSchedule{
+		task: &TickerTask{
+			Component: link.SourceID,
+			Link:      link.Name,
+			Schedule:  name,
+			Type:      config[configTypeKey],
+			Version:   version,
+			Config:    config,
+			Retry:     retry,
+			Timeout:   timeout,
+			Overlap:   config[overlapConfigKey],
+			Location:  location,
+			Jitter:    jitter,
+			StartAt:   startAt,
+			EndAt:     endAt,
+			MaxRuns:   maxRuns,
+			Blackouts: blackouts,
+			Critical:  config[criticalConfigKey] == "true",
+			key:       jobKey,
+			next:      next,
+
+			HistorySize: historySize,
+		},
+		jobDef:  jobDef,
+		options: jobOptions,
+	}, nil
+}
+
+// putSchedule creates the job for a schedule of a link, or updates the job
+// if the schedule already exists.
+func (t *Ticker) putSchedule(schedule *linkSchedule) error {
+	jobCtx := schedule.task
+	jobKey := jobCtx.key
+
+	t.lock.RLock()
+	existing, ok := t.taskList[jobKey]
+	t.lock.RUnlock()
+	if ok {
+		changed := getChangedKeys(existing.Config, jobCtx.Config)
+		if len(changed) == 0 {
+			t.provider.Logger.Info("link unchanged", "link", jobCtx.Link, "component", jobCtx.Component, "schedule", jobCtx.Schedule)
+			return nil
+		}
+
+		t.provider.Logger.Info("link updated", "link", jobCtx.Link, "component", jobCtx.Component, "schedule", jobCtx.Schedule, "changed", changed)
+		jobCtx.restore(existing.State())
+	} else {
+		t.loadState(jobCtx)
+	}
+	t.resizeHistory(jobCtx)
+
+	// Record jobs with an end straight away, so times which pass while the
+	// provider is down are skipped when the link is put again unchanged
+	if jobCtx.Type == configTypeOnce || !jobCtx.EndAt.IsZero() {
+		t.saveState(jobCtx)
 	}
 
-	job, err := t.tasks.NewJob(
-		jobDef,
+	// Jobs which have finished are not scheduled again when their link is
+	// put after a restart
+	if jobCtx.finished(time.Now()) {
+		t.provider.Logger.Info("task complete", "link", jobCtx.Link, "component", jobCtx.Component, "schedule", jobCtx.Schedule, "run_count", jobCtx.State().RunCount)
+		if ok {
+			t.finishTask(existing)
+		}
+		return nil
+	}
+
+	if jobCtx.next != nil {
+		jobCtx.runs = jobCtx.computedRuns(time.Now())
+		schedule.jobDef = nil
+		if len(jobCtx.runs) > 0 {
+			schedule.jobDef = gocron.OneTimeJob(gocron.OneTimeJobStartDateTimes(jobCtx.runs...))
+		}
+	}
+
+	// Start up jobs only fire once per link, not again on provider restart,
+	// and one-time jobs have nothing left to run once their times have passed
+	if schedule.jobDef == nil || jobCtx.Type == configTypeStartup && jobCtx.State().RunCount > 0 {
+		t.provider.Logger.Info("task already run", "link", jobCtx.Link, "component", jobCtx.Component, "schedule", jobCtx.Schedule)
+
+		if ok && existing.ID != uuid.Nil {
+			err := t.removeJob(existing)
+			if err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+				return err
+			}
+		}
+
+		t.setTask(jobCtx)
+		return nil
+	}
+
+	jobCtx.options = schedule.options
+	options := t.jobOptions(jobCtx)
+
+	s, err := t.getScheduler(jobCtx.Location)
+	if err != nil {
+		return err
+	}
+
+	// Jobs cannot move between schedulers, so a job moved to another time
+	// zone is removed and created again
+	update := ok && existing.ID != uuid.Nil
+	if update {
+		previous, err := t.getScheduler(existing.Location)
+		if err != nil {
+			return err
+		}
+		if previous != s {
+			err = previous.RemoveJob(existing.ID)
+			if err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+				return err
+			}
+			update = false
+		}
+	}
+
+	// Replace the job of a link which is put again in place, keeping its ID
+	// so the old schedule stops as the new one starts. Replacing the job
+	// cancels a run in progress, so it is given time to finish first
+	if update {
+		if !waitInFlight(existing, existing.Timeout) {
+			t.provider.Logger.Warn("task still running, replacing job", "id", existing.ID.String(), "link", existing.Link, "timeout", existing.Timeout)
+		}
+
+		job, err := s.Update(
+			existing.ID,
+			schedule.jobDef,
+			gocron.NewTask(t.TaskFunc, jobCtx),
+			options...,
+		)
+		if err != nil {
+			return err
+		}
+		jobCtx.ID = job.ID()
+		jobCtx.setJob(job)
+
+		t.setTask(jobCtx)
+		return nil
+	}
+
+	missed, err := getMissedRuns(jobCtx.Config, jobCtx.State().LastScheduled, time.Now())
+	if err != nil {
+		return err
+	}
+
+	job, err := s.NewJob(
+		schedule.jobDef,
 		gocron.NewTask(t.TaskFunc, jobCtx),
+		options...,
 	)
 	if err != nil {
 		return err
 	}
 	jobCtx.ID = job.ID()
-	t.taskList[jobKey] = jobCtx
+	jobCtx.setJob(job)
 
+	if len(missed) > 0 && !ok {
+		t.scheduleCatchUp(jobCtx, missed)
+	}
+
+	t.setTask(jobCtx)
 	return nil
 }
 
-func (t *Ticker) handleDelTargetLink(link provider.InterfaceLinkDefinition) error {
-	t.provider.Logger.Info("handleDelTargetLink", "link", link)
+// waitInFlight waits for the runs of a task in progress to finish, for up to
+// the given timeout, returning whether they finished.
+func waitInFlight(task *TickerTask, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for task.inFlight.Load() > 0 {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(inFlightPollInterval)
+	}
+	return true
+}
+
+// jobOptions returns the options of the job of a link task, limited to its
+// active window.
+func (t *Ticker) jobOptions(task *TickerTask) []gocron.JobOption {
+	return slices.Concat(task.options, task.windowOptions(time.Now()), []gocron.JobOption{task.nextRunListener()})
+}
+
+// setTask records the task of a link schedule, replacing any previous task.
+// Tasks with an end time are finished once it has passed, leaving time for a
+// run at the end time to complete.
+func (t *Ticker) setTask(task *TickerTask) {
+	if !task.EndAt.IsZero() && task.ID != uuid.Nil {
+		task.mu.Lock()
+		task.endTimer = time.AfterFunc(time.Until(task.EndAt)+task.Timeout, func() { t.finishTask(task) })
+		task.mu.Unlock()
+	}
+	if task.next != nil && task.ID != uuid.Nil {
+		task.mu.Lock()
+		task.refreshTimer = time.AfterFunc(time.Until(task.refreshAtLocked()), func() { t.refreshTask(task) })
+		task.mu.Unlock()
+	}
+
+	t.lock.Lock()
+	previous, ok := t.taskList[task.key]
+	t.taskList[task.key] = task
+	t.lock.Unlock()
+
+	if ok {
+		previous.stopTimers()
+	}
+}
+
+// refreshTask replaces the computed runs of a task with the runs from now,
+// so its job keeps running past the end of the current batch.
+func (t *Ticker) refreshTask(task *TickerTask) {
+	t.links.Lock()
+	defer t.links.Unlock()
+
+	t.lock.RLock()
+	current, ok := t.taskList[task.key]
+	t.lock.RUnlock()
+	if !ok || current != task {
+		return
+	}
+
+	// Replacing the job cancels a run in progress, so wait for it to finish
+	if task.inFlight.Load() > 0 {
+		task.mu.Lock()
+		task.refreshTimer = time.AfterFunc(time.Second, func() { t.refreshTask(task) })
+		task.mu.Unlock()
+		return
+	}
+
+	runs := task.computedRuns(time.Now())
+	if len(runs) == 0 {
+		t.finishTask(task)
+		return
+	}
+
+	s, err := t.getScheduler(task.Location)
+	if err == nil {
+		var job gocron.Job
+		job, err = s.Update(
+			task.ID,
+			gocron.OneTimeJob(gocron.OneTimeJobStartDateTimes(runs...)),
+			gocron.NewTask(t.TaskFunc, task),
+			t.jobOptions(task)...,
+		)
+		if err == nil {
+			task.setJob(job)
+		}
+	}
+	if err != nil {
+		t.provider.Logger.Error("error: Update computed runs", "error", err, "id", task.ID.String(), "link", task.Link)
+		return
+	}
+
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	task.runs = runs
+	task.refreshTimer = time.AfterFunc(time.Until(task.refreshAtLocked()), func() { t.refreshTask(task) })
+}
+
+// finishTask removes a link task which has reached the end of its active
+// window or used up its runs. Its state is kept, so it does not run again
+// when the link is put after a restart.
+func (t *Ticker) finishTask(task *TickerTask) {
+	t.lock.Lock()
+	current, ok := t.taskList[task.key]
+	if ok && current == task {
+		delete(t.taskList, task.key)
+	}
+	t.lock.Unlock()
+	if !ok || current != task {
+		return
+	}
+
+	task.stopTimers()
+	if task.ID != uuid.Nil {
+		err := t.removeJob(task)
+		if err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+			t.provider.Logger.Error("error: RemoveJob", "error", err, "id", task.ID.String(), "link", task.Link)
+		}
+	}
+	t.provider.Logger.Info("task complete", "id", task.ID.String(), "link", task.Link, "component", task.Component, "schedule", task.Schedule, "run_count", task.State().RunCount)
+}
+
+// getLinkTasks returns the tasks of every schedule of a link, keyed by their
+// schedule key.
+func (t *Ticker) getLinkTasks(link provider.InterfaceLinkDefinition) map[string]*TickerTask {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	jobKey := getJobKey(link)
-	taskId, ok := t.taskList[jobKey]
-	if !ok {
-		return ErrTickerNotFound
+	tasks := map[string]*TickerTask{}
+	for key, task := range t.taskList {
+		if key == jobKey || strings.HasPrefix(key, jobKey+"/") {
+			tasks[key] = task
+		}
 	}
+	return tasks
+}
+
+// removeTask removes the job of a link schedule along with its state.
+func (t *Ticker) removeTask(key string, task *TickerTask) error {
+	task.stopTimers()
+	if task.ID != uuid.Nil {
+		err := t.removeJob(task)
+		if err != nil {
+			return err
+		}
+	}
+
+	t.lock.Lock()
+	delete(t.taskList, key)
+	t.lock.Unlock()
 
-	err := t.tasks.RemoveJob(taskId.ID)
+	t.deleteState(key)
+	t.deleteHistory(key)
+	return nil
+}
+
+// removeJob removes the job of a task from the scheduler it was created on.
+func (t *Ticker) removeJob(task *TickerTask) error {
+	s, err := t.getScheduler(task.Location)
 	if err != nil {
 		return err
 	}
+	return s.RemoveJob(task.ID)
+}
+
+// scheduleCatchUp fires the runs a link missed while the provider was down
+// as a one-off job, so they are coordinated between replicas like any other
+// run.
+func (t *Ticker) scheduleCatchUp(task *TickerTask, missed []time.Time) {
+	t.provider.Logger.Warn("task missed runs", "id", task.ID.String(), "component", task.Component, "link", task.Link, "missed", len(missed), "first", missed[0], "last", missed[len(missed)-1])
+
+	remove := func(id uuid.UUID, _ string) {
+		t.tasks.RemoveJob(id)
+	}
+	_, err := t.tasks.NewJob(
+		gocron.OneTimeJob(gocron.OneTimeJobStartImmediately()),
+		gocron.NewTask(t.CatchUpFunc, task, missed),
+		gocron.WithName(task.key+".catch-up"),
+		gocron.WithEventListeners(
+			gocron.AfterJobRuns(remove),
+			gocron.AfterJobRunsWithError(func(id uuid.UUID, name string, _ error) { remove(id, name) }),
+			gocron.AfterLockError(func(id uuid.UUID, name string, _ error) { remove(id, name) }),
+		),
+	)
+	if err != nil {
+		t.provider.Logger.Error("error: NewJob catch-up", "error", err, "id", task.ID.String(), "link", task.Link)
+	}
+}
+
+func (t *Ticker) handleDelTargetLink(link provider.InterfaceLinkDefinition) error {
+	t.provider.Logger.Info("handleDelTargetLink", "link", link)
+
+	t.links.Lock()
+	defer t.links.Unlock()
+
+	tasks := t.getLinkTasks(link)
+	if len(tasks) == 0 {
+		return ErrTickerNotFound
+	}
 
-	delete(t.taskList, jobKey)
+	for key, task := range tasks {
+		err := t.removeTask(key, task)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// handleHealthCheck reports the provider as unhealthy if its scheduler is
+// not running or any of its tasks are unhealthy, naming those tasks.
 func (t *Ticker) handleHealthCheck() string {
+	now := time.Now()
+
+	t.lock.RLock()
+	started := t.started
+	jobs := 0
+	unhealthy := []string{}
+	for _, task := range t.taskList {
+		if task.ID == uuid.Nil {
+			continue
+		}
+
+		jobs++
+		if reason, ok := task.unhealthy(now, t.failureThreshold, t.maxSuccessAge); ok {
+			unhealthy = append(unhealthy, reason)
+		}
+	}
+	t.lock.RUnlock()
+	slices.Sort(unhealthy)
+
 	h := provider.HealthCheckResponse{
-		Healthy: true,
-		Message: "healthy",
+		Healthy: started && len(unhealthy) == 0,
+		Message: fmt.Sprintf("healthy, %d jobs", jobs),
+	}
+	if !started {
+		h.Message = "unhealthy, scheduler not started"
+	} else if len(unhealthy) > 0 {
+		h.Message = fmt.Sprintf("unhealthy, %d of %d jobs failing: %s", len(unhealthy), jobs, strings.Join(unhealthy, ", "))
+	}
+
+	if t.elector != nil {
+		leader, ok := t.elector.Leader()
+		if !ok {
+			leader = "unknown"
+		} else if leader == t.elector.ID() {
+			leader += " (this replica)"
+		}
+		h.Message += fmt.Sprintf(", leader: %s", leader)
 	}
 
 	data, err := json.Marshal(&h)
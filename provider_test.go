@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	gocronmocks "github.com/go-co-op/gocron/mocks/v2"
+	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
+	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.wasmcloud.dev/provider"
+	wrpc "wrpc.io/go"
 )
 
 func TestCreateTicker(t *testing.T) {
@@ -18,6 +28,213 @@ func TestCreateTicker(t *testing.T) {
 	assert.NotNil(t, ticker)
 }
 
+func TestConfigure(t *testing.T) {
+	t.Run("default timeout", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, ticker.defaultTimeout)
+		assert.IsType(t, &FileStore{}, ticker.state)
+	})
+
+	t.Run("valid timeout", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"default_timeout": "1m",
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, ticker.defaultTimeout)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"default_timeout": "abcd",
+		}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("distributed lock", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"distributed": "lock",
+			"lock_bucket": "test_locks",
+			"lock_ttl":    "30s",
+		}, nc)
+		assert.NoError(t, err)
+		assert.NoError(t, ticker.Shutdown())
+	})
+
+	t.Run("distributed lock no connection", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"distributed": "lock",
+		}, nil)
+		assert.ErrorIs(t, err, ErrNoConnection)
+	})
+
+	t.Run("distributed leader", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"distributed":   "leader",
+			"leader_bucket": "test_leader",
+			"leader_lease":  "2s",
+		}, nc)
+		assert.NoError(t, err)
+		assert.NotNil(t, ticker.elector)
+
+		assert.NoError(t, ticker.Start())
+		assert.Eventually(t, func() bool {
+			return ticker.elector.IsLeader(context.Background()) == nil
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.NoError(t, ticker.Shutdown())
+	})
+
+	t.Run("invalid leader lease", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"distributed":  "leader",
+			"leader_lease": "100ms",
+		}, nil)
+		assert.ErrorIs(t, err, ErrInvalidLease)
+	})
+
+	t.Run("nats state store", func(t *testing.T) {
+		nc := runNatsServer(t)
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"state_store":  "nats",
+			"state_bucket": "test_state",
+		}, nc)
+		assert.NoError(t, err)
+		assert.IsType(t, &NatsStore{}, ticker.state)
+	})
+
+	t.Run("invalid state store", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"state_store": "abcd",
+		}, nil)
+		assert.ErrorIs(t, err, ErrInvalidStore)
+	})
+
+	t.Run("invalid distributed mode", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"distributed": "abcd",
+		}, nil)
+		assert.ErrorIs(t, err, ErrInvalidMode)
+	})
+
+	t.Run("timezone", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"state_store": "none",
+			"timezone":    "Europe/London",
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/London", ticker.location.String())
+	})
+
+	t.Run("blackout", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"state_store": "none",
+			"blackout":    "0 2 * * SAT for 4h;2026-11-01T00:00:00Z/2026-11-01T04:00:00Z",
+		}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, ticker.blackouts, 2)
+	})
+
+	t.Run("invalid blackout", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"blackout": "weekends",
+		}, nil)
+		assert.ErrorIs(t, err, ErrInvalidBlackout)
+		assert.ErrorContains(t, err, "key blackout")
+	})
+
+	t.Run("health thresholds", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), ticker.failureThreshold)
+
+		err = ticker.Configure(map[string]string{
+			"health_failure_threshold": "5",
+			"health_max_success_age":   "25h",
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), ticker.failureThreshold)
+		assert.Equal(t, 25*time.Hour, ticker.maxSuccessAge)
+
+		err = ticker.Configure(map[string]string{
+			"health_failure_threshold": "-1",
+		}, nil)
+		assert.ErrorIs(t, err, ErrInvalidConfigValue)
+
+		err = ticker.Configure(map[string]string{
+			"health_max_success_age": "a day",
+		}, nil)
+		assert.ErrorContains(t, err, "key health_max_success_age")
+	})
+
+	t.Run("history file", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "history.json")
+		err = ticker.Configure(map[string]string{
+			"history_file": path,
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, path, ticker.history.path)
+
+		err = ticker.Configure(map[string]string{
+			"history_file": t.TempDir(),
+		}, nil)
+		assert.ErrorContains(t, err, "key history_file")
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+
+		err = ticker.Configure(map[string]string{
+			"timezone": "Mars/Olympus_Mons",
+		}, nil)
+		assert.ErrorIs(t, err, ErrInvalidConfigValue)
+	})
+}
+
 func TestStart(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	s := gocronmocks.NewMockScheduler(ctrl)
@@ -56,12 +273,15 @@ func TestPutTargetLink(t *testing.T) {
 			},
 		}
 		mockId := uuid.New()
+		nextRun := time.Now().Add(10 * time.Second)
 
 		s.EXPECT().NewJob(
 			gomock.Any(),
 			gomock.Any(),
+			gomock.Any(),
 		).Return(j, nil).Times(1)
 		j.EXPECT().ID().Return(mockId).Times(1)
+		j.EXPECT().NextRun().Return(nextRun, nil).Times(1)
 
 		testLink := provider.InterfaceLinkDefinition{
 			Name:     "default",
@@ -77,6 +297,9 @@ func TestPutTargetLink(t *testing.T) {
 		myJob, ok := ticker.taskList["default.my-id"]
 		assert.True(t, ok)
 		assert.Equal(t, mockId, myJob.ID)
+
+		// The first run reports the time it was scheduled for
+		assert.True(t, nextRun.Equal(myJob.scheduledAt(nextRun.Add(time.Second))))
 	})
 
 	t.Run("invalid config", func(t *testing.T) {
@@ -106,6 +329,74 @@ func TestPutTargetLink(t *testing.T) {
 		assert.False(t, ok)
 	})
 
+	t.Run("valid link, skip overlap", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().NewJob(
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).Return(j, nil).Times(1)
+		j.EXPECT().ID().Return(uuid.New()).Times(1)
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":  "10s",
+				"overlap": "skip",
+			},
+		}
+
+		err := ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+
+		myJob, ok := ticker.taskList["default.my-id"]
+		assert.True(t, ok)
+		assert.Equal(t, "skip", myJob.Overlap)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":  "10s",
+				"version": "abcd",
+			},
+		}
+
+		err := ticker.handlePutTargetLink(testLink)
+		assert.ErrorIs(t, err, ErrInvalidVersion)
+
+		_, ok := ticker.taskList["default.my-id"]
+		assert.False(t, ok)
+	})
+
 	t.Run("new job error", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		s := gocronmocks.NewMockScheduler(ctrl)
@@ -122,6 +413,7 @@ func TestPutTargetLink(t *testing.T) {
 		s.EXPECT().NewJob(
 			gomock.Any(),
 			gomock.Any(),
+			gomock.Any(),
 		).Return(nil, testError).Times(1)
 
 		testLink := provider.InterfaceLinkDefinition{
@@ -139,30 +431,37 @@ func TestPutTargetLink(t *testing.T) {
 		_, ok := ticker.taskList["default.my-id"]
 		assert.False(t, ok)
 	})
-}
 
-func TestDelTargetLink(t *testing.T) {
-	t.Run("valid delete", func(t *testing.T) {
+	t.Run("restores state", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		s := gocronmocks.NewMockScheduler(ctrl)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		lastSuccess := time.Now().Add(-time.Minute)
+		err = store.Save(context.Background(), "default.my-id", TaskState{
+			LastSuccess: lastSuccess,
+			RunCount:    12,
+		})
+		assert.NoError(t, err)
 
 		ticker := Ticker{
-			tasks: s,
-			taskList: map[string]*TickerTask{
-				"default.my-id": {
-					Component: "my-component",
-					ID:        uuid.New(),
-					Type:      "cron",
-				},
-			},
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			state:    store,
 			provider: &provider.WasmcloudProvider{
 				Logger: slog.Default(),
 			},
 		}
 
-		s.EXPECT().RemoveJob(
+		s.EXPECT().NewJob(
 			gomock.Any(),
-		).Return(nil).Times(1)
+			gomock.Any(),
+			gomock.Any(),
+		).Return(j, nil).Times(1)
+		j.EXPECT().ID().Return(uuid.New()).Times(1)
 
 		testLink := provider.InterfaceLinkDefinition{
 			Name:     "default",
@@ -172,74 +471,1630 @@ func TestDelTargetLink(t *testing.T) {
 			},
 		}
 
-		err := ticker.handleDelTargetLink(testLink)
+		err = ticker.handlePutTargetLink(testLink)
 		assert.NoError(t, err)
 
-		_, ok := ticker.taskList["default.my-id"]
-		assert.False(t, ok)
+		state := ticker.taskList["default.my-id"].State()
+		assert.Equal(t, uint64(12), state.RunCount)
+		assert.True(t, lastSuccess.Equal(state.LastSuccess))
 	})
 
-	t.Run("not found", func(t *testing.T) {
+	t.Run("misfire catch up", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		s := gocronmocks.NewMockScheduler(ctrl)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id", TaskState{
+			LastScheduled: time.Now().Add(-time.Hour),
+			RunCount:      5,
+		})
+		assert.NoError(t, err)
 
 		ticker := Ticker{
 			tasks:    s,
 			taskList: make(map[string]*TickerTask),
+			state:    store,
 			provider: &provider.WasmcloudProvider{
 				Logger: slog.Default(),
 			},
 		}
 
+		gomock.InOrder(
+			s.EXPECT().NewJob(
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+			).Return(j, nil).Times(1),
+			s.EXPECT().NewJob(
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+				gomock.Any(),
+			).Return(j, nil).Times(1),
+		)
+		j.EXPECT().ID().Return(uuid.New()).Times(1)
+
 		testLink := provider.InterfaceLinkDefinition{
 			Name:     "default",
 			SourceID: "my-id",
 			TargetConfig: map[string]string{
-				"period": "10s",
+				"period":  "10m",
+				"misfire": "run-once",
 			},
 		}
 
-		err := ticker.handleDelTargetLink(testLink)
-		assert.Error(t, err)
-		assert.Equal(t, ErrTickerNotFound, err)
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
 	})
 
-	t.Run("remove error", func(t *testing.T) {
+	t.Run("invalid misfire", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		s := gocronmocks.NewMockScheduler(ctrl)
 
 		ticker := Ticker{
-			tasks: s,
-			taskList: map[string]*TickerTask{
-				"default.my-id": {
-					Component: "my-component",
-					ID:        uuid.New(),
-					Type:      "cron",
-				},
-			},
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
 			provider: &provider.WasmcloudProvider{
 				Logger: slog.Default(),
 			},
 		}
-		testError := errors.New("test error")
-
-		s.EXPECT().RemoveJob(
-			gomock.Any(),
-		).Return(testError).Times(1)
 
 		testLink := provider.InterfaceLinkDefinition{
 			Name:     "default",
 			SourceID: "my-id",
 			TargetConfig: map[string]string{
-				"period": "10s",
+				"period":  "10m",
+				"misfire": "abcd",
 			},
 		}
 
-		err := ticker.handleDelTargetLink(testLink)
-		assert.Error(t, err)
-		assert.Equal(t, testError, err)
+		err := ticker.handlePutTargetLink(testLink)
+		assert.ErrorIs(t, err, ErrInvalidMisfire)
+	})
 
-		_, ok := ticker.taskList["default.my-id"]
+	t.Run("once", func(t *testing.T) {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.state = store
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		first := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		second := first.Add(24 * time.Hour)
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":  "once",
+				"times": second.Format(time.RFC3339) + "," + first.Format(time.RFC3339),
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		nextRun, ok := ticker.taskList["default.my-id"].NextRun()
+		assert.True(t, ok)
+		assert.True(t, first.Equal(nextRun))
+
+		// The link is recorded before it first runs
+		_, ok, err = store.Load(context.Background(), "default.my-id")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("once in the past", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":  "once",
+				"times": "2020-01-01T00:00:00Z",
+			},
+		})
+		assert.ErrorIs(t, err, ErrTimeInPast)
+		assert.ErrorContains(t, err, "key times: 2020-01-01T00:00:00Z")
+		assert.Empty(t, ticker.taskList)
+	})
+
+	t.Run("once after restart", func(t *testing.T) {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id", TaskState{})
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id/done", TaskState{
+			LastScheduled: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			RunCount:      1,
+		})
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.state = store
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		future := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":  "once",
+				"times": "2020-01-01T00:00:00Z," + future.Format(time.RFC3339),
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		nextRun, ok := ticker.taskList["default.my-id"].NextRun()
+		assert.True(t, ok)
+		assert.True(t, future.Equal(nextRun))
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"schedule.done.type":  "once",
+				"schedule.done.times": "2020-01-01T00:00:00Z",
+			},
+		})
+		assert.NoError(t, err)
+
+		task, ok := ticker.taskList["default.my-id/done"]
 		assert.True(t, ok)
+		assert.Equal(t, uuid.Nil, task.ID)
+		assert.Equal(t, uint64(1), task.State().RunCount)
+	})
+
+	t.Run("once changed after restart", func(t *testing.T) {
+		config := map[string]string{
+			"type":  "once",
+			"times": "2020-01-01T00:00:00Z",
+		}
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id", TaskState{
+			LastScheduled: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			RunCount:      1,
+			ConfigHash:    getConfigHash(config),
+		})
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.state = store
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":    "once",
+				"times":   "2020-01-01T00:00:00Z",
+				"timeout": "5s",
+			},
+		})
+		assert.ErrorIs(t, err, ErrTimeInPast)
+		assert.Empty(t, ticker.taskList)
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:         "default",
+			SourceID:     "my-id",
+			TargetConfig: config,
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, ticker.taskList, "default.my-id")
+	})
+
+	t.Run("active window", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":   "1m",
+				"start_at": startAt.Format(time.RFC3339),
+				"end_at":   startAt.Add(24 * time.Hour).Format(time.RFC3339),
+				"max_runs": "10",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		task := ticker.taskList["default.my-id"]
+		assert.Equal(t, uint64(10), task.MaxRuns)
+		nextRun, ok := task.NextRun()
+		assert.True(t, ok)
+		assert.True(t, startAt.Equal(nextRun))
+	})
+
+	t.Run("end at removes task", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":  "1h",
+				"timeout": "1ms",
+				"end_at":  time.Now().Add(time.Second).Format(time.RFC3339),
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+
+		assert.Eventually(t, func() bool {
+			ticker.lock.RLock()
+			defer ticker.lock.RUnlock()
+			return len(ticker.taskList) == 0
+		}, 3*time.Second, 50*time.Millisecond)
+		assert.Empty(t, ticker.tasks.Jobs())
+	})
+
+	t.Run("end at in the past", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "1h",
+				"end_at": "2020-01-01T00:00:00Z",
+			},
+		})
+		assert.ErrorIs(t, err, ErrTimeInPast)
+		assert.ErrorContains(t, err, "key end_at")
+	})
+
+	t.Run("finished after restart", func(t *testing.T) {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id/limited", TaskState{RunCount: 3})
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id/ended", TaskState{})
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.state = store
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"schedule.limited.period":   "1h",
+				"schedule.limited.max_runs": "3",
+				"schedule.ended.period":     "1h",
+				"schedule.ended.end_at":     "2020-01-01T00:00:00Z",
+				"schedule.running.period":   "1h",
+				"schedule.running.max_runs": "3",
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, ticker.taskList, 1)
+		assert.Contains(t, ticker.taskList, "default.my-id/running")
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+	})
+
+	t.Run("max runs finishes task", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":   "1h",
+				"max_runs": "2",
+			},
+		})
+		assert.NoError(t, err)
+
+		task := ticker.taskList["default.my-id"]
+		_, ok := task.invocation(time.Now())
+		assert.True(t, ok)
+		assert.False(t, task.finished(time.Now()))
+		_, ok = task.invocation(time.Now())
+		assert.True(t, ok)
+		assert.True(t, task.finished(time.Now()))
+
+		// No more runs are reserved once they are used up
+		_, ok = task.invocation(time.Now())
+		assert.False(t, ok)
+		assert.Equal(t, uint64(2), task.State().RunCount)
+
+		ticker.finishTask(task)
+		assert.Empty(t, ticker.taskList)
+		assert.Empty(t, ticker.tasks.Jobs())
+	})
+
+	t.Run("max runs counted by task", func(t *testing.T) {
+		invoker := &countingInvoker{}
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.invoker = func(string) wrpc.Invoker { return invoker }
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":   "1h",
+				"max_runs": "2",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		task := ticker.taskList["default.my-id"]
+		for range 2 {
+			assert.NoError(t, task.job.RunNow())
+		}
+		assert.Eventually(t, func() bool {
+			ticker.lock.RLock()
+			defer ticker.lock.RUnlock()
+			return len(ticker.taskList) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(2), invoker.calls.Load())
+		assert.Equal(t, uint64(2), task.State().RunCount)
+		assert.Empty(t, ticker.tasks.Jobs())
+	})
+
+	t.Run("max runs with blackout", func(t *testing.T) {
+		invoker := &countingInvoker{}
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.invoker = func(string) wrpc.Invoker { return invoker }
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		now := time.Now().UTC()
+		config := map[string]string{
+			"period":   "1h",
+			"max_runs": "2",
+			"blackout": now.Add(-time.Hour).Format(time.RFC3339) + "/" + now.Add(time.Hour).Format(time.RFC3339),
+		}
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:         "default",
+			SourceID:     "my-id",
+			TargetConfig: config,
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		// Skipped runs leave the task with all of its runs
+		task := ticker.taskList["default.my-id"]
+		for range 3 {
+			assert.NoError(t, task.job.RunNow())
+		}
+		assert.Eventually(t, func() bool {
+			task.mu.Lock()
+			defer task.mu.Unlock()
+			return task.skipped == 3
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Contains(t, ticker.taskList, "default.my-id")
+		assert.Equal(t, uint64(0), task.State().RunCount)
+		assert.Equal(t, int32(0), invoker.calls.Load())
+
+		delete(config, "blackout")
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:         "default",
+			SourceID:     "my-id",
+			TargetConfig: config,
+		})
+		assert.NoError(t, err)
+
+		task = ticker.taskList["default.my-id"]
+		for range 2 {
+			assert.NoError(t, task.job.RunNow())
+		}
+		assert.Eventually(t, func() bool {
+			ticker.lock.RLock()
+			defer ticker.lock.RUnlock()
+			return len(ticker.taskList) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(2), invoker.calls.Load())
+		assert.Empty(t, ticker.tasks.Jobs())
+	})
+
+	t.Run("startup already run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id", TaskState{
+			LastSuccess: time.Now(),
+			RunCount:    1,
+		})
+		assert.NoError(t, err)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			state:    store,
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":  "startup",
+				"delay": "1s",
+			},
+		}
+
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+
+		task, ok := ticker.taskList["default.my-id"]
+		assert.True(t, ok)
+		assert.Equal(t, uuid.Nil, task.ID)
+
+		err = ticker.handleDelTargetLink(testLink)
+		assert.NoError(t, err)
+
+		_, ok, err = store.Load(context.Background(), "default.my-id")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("business calendar", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		now := time.Now().UTC()
+		first := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+		if !first.After(now) {
+			first = first.AddDate(0, 0, 1)
+		}
+		holidays := first.Format(time.DateOnly) + "," + first.AddDate(0, 0, 1).Format(time.DateOnly)
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":          "daily",
+				"at":            "09:00",
+				"timezone":      "UTC",
+				"calendar":      holidays,
+				"business_days": "mon,tue,wed,thu,fri,sat,sun",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		task := ticker.taskList["default.my-id"]
+		nextRun, ok := task.NextRun()
+		assert.True(t, ok)
+		assert.Equal(t, first.AddDate(0, 0, 2), nextRun.UTC())
+		assert.Greater(t, len(task.runs), 300)
+
+		ticker.refreshTask(task)
+		nextRun, ok = task.NextRun()
+		assert.True(t, ok)
+		assert.Equal(t, first.AddDate(0, 0, 2), nextRun.UTC())
+		s, err := ticker.getScheduler(task.Location)
+		assert.NoError(t, err)
+		assert.Len(t, s.Jobs(), 1)
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period":   "1h",
+				"calendar": holidays,
+			},
+		})
+		assert.ErrorIs(t, err, ErrInvalidConfigValue)
+	})
+
+	t.Run("rrule", func(t *testing.T) {
+		store, err := NewFileStore(filepath.Join(t.TempDir(), "state.json"))
+		assert.NoError(t, err)
+		err = store.Save(context.Background(), "default.my-id/ended", TaskState{RunCount: 3})
+		assert.NoError(t, err)
+
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.state = store
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"schedule.monthly.type":    "rrule",
+				"schedule.monthly.rrule":   "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+				"schedule.monthly.dtstart": "2020-01-01T00:00:00Z",
+				"schedule.ended.type":      "rrule",
+				"schedule.ended.rrule":     "DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;COUNT=3",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		nextRun, ok := ticker.taskList["default.my-id/monthly"].NextRun()
+		assert.True(t, ok)
+		assert.Equal(t, time.Monday, nextRun.UTC().Weekday())
+		assert.LessOrEqual(t, nextRun.UTC().Day(), 7)
+		assert.Equal(t, 9, nextRun.UTC().Hour())
+
+		ended := ticker.taskList["default.my-id/ended"]
+		assert.Equal(t, uuid.Nil, ended.ID)
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+	})
+
+	t.Run("rrule batch boundary", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":  "rrule",
+				"rrule": "FREQ=MINUTELY;BYSECOND=0",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		// Runs are scheduled in batches, refreshed half way through
+		task := ticker.taskList["default.my-id"]
+		task.mu.Lock()
+		runs := slices.Clone(task.runs)
+		refreshAt := task.refreshAtLocked()
+		task.mu.Unlock()
+		assert.Len(t, runs, computedRunsMax)
+		assert.True(t, refreshAt.After(runs[0]))
+		assert.True(t, refreshAt.Before(runs[len(runs)-1]))
+
+		// The next batch carries on from the end of the last, as if it were
+		// computed from any time between two runs
+		last := runs[len(runs)-1]
+		next := task.computedRuns(last.Add(-time.Second))
+		assert.Equal(t, last, next[0])
+		next = task.computedRuns(last)
+		assert.Equal(t, last.Add(time.Minute), next[0])
+
+		// Refreshing keeps the next run of the job, unless it has just fired
+		nextRun, ok := task.NextRun()
+		assert.True(t, ok)
+		ticker.refreshTask(task)
+		refreshed, ok := task.NextRun()
+		assert.True(t, ok)
+		assert.WithinDuration(t, nextRun, refreshed, time.Minute)
+	})
+
+	t.Run("solar", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":      "solar",
+				"latitude":  "51.5074",
+				"longitude": "-0.1278",
+				"event":     "sunset",
+				"offset":    "30m",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		task := ticker.taskList["default.my-id"]
+		assert.Equal(t, "sunset 30m", getScheduleDescription(task.Config))
+		nextRun, ok := task.NextRun()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(12*time.Hour), nextRun, 12*time.Hour+time.Minute)
+		assert.Greater(t, len(task.runs), 300)
+	})
+}
+
+func TestUpdateTargetLink(t *testing.T) {
+	t.Run("unchanged config", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		jobID := uuid.New()
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				"default.my-id": {
+					Component: "my-id",
+					ID:        jobID,
+					Type:      "interval",
+					Config: map[string]string{
+						"type":   "interval",
+						"period": "10s",
+					},
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "10s",
+			},
+		}
+
+		err := ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Equal(t, jobID, ticker.taskList["default.my-id"].ID)
+	})
+
+	t.Run("changed period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+		jobID := uuid.New()
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				"default.my-id": {
+					Component: "my-id",
+					ID:        jobID,
+					Type:      "interval",
+					Config: map[string]string{
+						"type":   "interval",
+						"period": "10s",
+					},
+					runCount: 3,
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().Update(
+			jobID,
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).Return(j, nil).Times(1)
+		j.EXPECT().ID().Return(jobID).Times(1)
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "1m",
+			},
+		}
+
+		err := ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+
+		task := ticker.taskList["default.my-id"]
+		assert.Len(t, ticker.taskList, 1)
+		assert.Equal(t, jobID, task.ID)
+		assert.Equal(t, "1m", task.Config["period"])
+		assert.Equal(t, uint64(3), task.State().RunCount)
+	})
+
+	t.Run("run in progress", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+		jobID := uuid.New()
+
+		existing := &TickerTask{
+			Component: "my-id",
+			ID:        jobID,
+			Type:      "interval",
+			Timeout:   5 * time.Second,
+			Config: map[string]string{
+				"type":   "interval",
+				"period": "10s",
+			},
+		}
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				"default.my-id": existing,
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		// The job is only replaced once the run in progress has finished,
+		// as replacing it cancels the run
+		var finished atomic.Bool
+		existing.inFlight.Add(1)
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			finished.Store(true)
+			existing.inFlight.Add(-1)
+		}()
+
+		s.EXPECT().Update(
+			jobID,
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).DoAndReturn(func(uuid.UUID, gocron.JobDefinition, gocron.Task, ...gocron.JobOption) (gocron.Job, error) {
+			assert.True(t, finished.Load())
+			return j, nil
+		}).Times(1)
+		j.EXPECT().ID().Return(jobID).Times(1)
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "1m",
+			},
+		}
+
+		err := ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Equal(t, "1m", ticker.taskList["default.my-id"].Config["period"])
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		jobID := uuid.New()
+		testError := errors.New("test error")
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				"default.my-id": {
+					Component: "my-id",
+					ID:        jobID,
+					Type:      "interval",
+					Config: map[string]string{
+						"type":   "interval",
+						"period": "10s",
+					},
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().Update(
+			jobID,
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, testError).Times(1)
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "1m",
+			},
+		}
+
+		err := ticker.handlePutTargetLink(testLink)
+		assert.Equal(t, testError, err)
+		assert.Equal(t, "10s", ticker.taskList["default.my-id"].Config["period"])
+	})
+
+	t.Run("no duplicate job", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "1h",
+			},
+		}
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		jobID := ticker.taskList["default.my-id"].ID
+
+		testLink.TargetConfig = map[string]string{
+			"period": "2h",
+		}
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+
+		jobs := ticker.tasks.Jobs()
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, jobID, jobs[0].ID())
+		assert.Equal(t, jobID, ticker.taskList["default.my-id"].ID)
+
+		ticker.Start()
+		nextRun, ok := ticker.taskList["default.my-id"].NextRun()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), nextRun, time.Minute)
+	})
+
+	t.Run("multiple schedules", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		defer ticker.Shutdown()
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"timeout":                "5s",
+				"schedule.daytime.type":  "cron",
+				"schedule.daytime.cron":  "*/5 8-18 * * *",
+				"schedule.midnight.type": "cron",
+				"schedule.midnight.cron": "0 0 * * *",
+			},
+		}
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Len(t, ticker.tasks.Jobs(), 2)
+
+		daytime, ok := ticker.taskList["default.my-id/daytime"]
+		assert.True(t, ok)
+		assert.Equal(t, "daytime", daytime.Schedule)
+		assert.Equal(t, 5*time.Second, daytime.Timeout)
+		midnight, ok := ticker.taskList["default.my-id/midnight"]
+		assert.True(t, ok)
+		assert.Equal(t, "0 0 * * *", midnight.Config["cron"])
+
+		// Removing a schedule from the link removes its job
+		testLink.TargetConfig = map[string]string{
+			"schedules": `{"daytime": {"type": "cron", "cron": "*/5 8-18 * * *", "timeout": "5s"}}`,
+		}
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+		assert.Len(t, ticker.taskList, 1)
+		assert.Equal(t, daytime.ID, ticker.taskList["default.my-id/daytime"].ID)
+
+		// An invalid schedule leaves the existing jobs untouched
+		testLink.TargetConfig = map[string]string{
+			"schedule.daytime.period":  "10m",
+			"schedule.midnight.period": "abcd",
+		}
+		err = ticker.handlePutTargetLink(testLink)
+		assert.ErrorContains(t, err, "schedule midnight")
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+
+		err = ticker.handleDelTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Empty(t, ticker.tasks.Jobs())
+		assert.Empty(t, ticker.taskList)
+	})
+}
+
+func TestDelTargetLink(t *testing.T) {
+	t.Run("valid delete", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				"default.my-id": {
+					Component: "my-component",
+					ID:        uuid.New(),
+					Type:      "cron",
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().RemoveJob(
+			gomock.Any(),
+		).Return(nil).Times(1)
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "10s",
+			},
+		}
+
+		err := ticker.handleDelTargetLink(testLink)
+		assert.NoError(t, err)
+
+		_, ok := ticker.taskList["default.my-id"]
+		assert.False(t, ok)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "10s",
+			},
+		}
+
+		err := ticker.handleDelTargetLink(testLink)
+		assert.Error(t, err)
+		assert.Equal(t, ErrTickerNotFound, err)
+	})
+
+	t.Run("remove error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				"default.my-id": {
+					Component: "my-component",
+					ID:        uuid.New(),
+					Type:      "cron",
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+		testError := errors.New("test error")
+
+		s.EXPECT().RemoveJob(
+			gomock.Any(),
+		).Return(testError).Times(1)
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "10s",
+			},
+		}
+
+		err := ticker.handleDelTargetLink(testLink)
+		assert.Error(t, err)
+		assert.Equal(t, testError, err)
+
+		_, ok := ticker.taskList["default.my-id"]
+		assert.True(t, ok)
+	})
+}
+
+func TestTaskInvocation(t *testing.T) {
+	t.Run("first run", func(t *testing.T) {
+		task := TickerTask{
+			Component: "my-component",
+			ID:        uuid.New(),
+			Link:      "default",
+			Type:      "interval",
+		}
+		firedAt := time.Now()
+
+		invocation, _ := task.invocation(firedAt)
+		assert.Equal(t, task.ID.String(), invocation.JobId)
+		assert.Equal(t, "default", invocation.LinkName)
+		assert.Equal(t, "interval", invocation.ScheduleType)
+		assert.Equal(t, uint64(firedAt.UnixMilli()), invocation.ScheduledAt)
+		assert.Equal(t, uint64(firedAt.UnixMilli()), invocation.FiredAt)
+		assert.Equal(t, uint64(1), invocation.RunCount)
+		assert.Equal(t, uint32(1), invocation.Attempt)
+	})
+
+	t.Run("scheduled run", func(t *testing.T) {
+		firedAt := time.Now()
+		scheduledAt := firedAt.Add(-50 * time.Millisecond)
+		task := TickerTask{
+			ID:       uuid.New(),
+			runCount: 4,
+			nextRun:  scheduledAt,
+		}
+
+		invocation, _ := task.invocation(firedAt)
+		assert.Equal(t, uint64(scheduledAt.UnixMilli()), invocation.ScheduledAt)
+		assert.Equal(t, uint64(firedAt.UnixMilli()), invocation.FiredAt)
+		assert.Equal(t, uint64(5), invocation.RunCount)
+	})
+
+	t.Run("max runs", func(t *testing.T) {
+		task := TickerTask{
+			ID:      uuid.New(),
+			MaxRuns: 3,
+		}
+
+		// Runs fired together cannot reserve more than the max runs
+		var reserved atomic.Int32
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, ok := task.invocation(time.Now()); ok {
+					reserved.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(3), reserved.Load())
+		assert.Equal(t, uint64(3), task.State().RunCount)
+	})
+
+	t.Run("skipped runs", func(t *testing.T) {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+
+		err = ticker.Configure(map[string]string{"state_store": "none"}, nil)
+		assert.NoError(t, err)
+
+		start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		clock := clockwork.NewFakeClockAt(start)
+		ticker.options = append(ticker.options, gocron.WithClock(clock))
+		ticker.tasks, err = ticker.newScheduler(ticker.options...)
+		assert.NoError(t, err)
+		t.Cleanup(func() { ticker.Shutdown() })
+
+		err = ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"period": "10s",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.taskList["default.my-id"].setPaused(true)
+		ticker.Start()
+
+		// Each skipped run reports its own scheduled time. Runs fire a moment
+		// after they are due, as they would on a real clock
+		task := ticker.taskList["default.my-id"]
+		for i := range 2 {
+			clock.Advance(10*time.Second + time.Millisecond)
+			assert.Eventually(t, func() bool {
+				nextRun, _ := task.NextRun()
+				return len(ticker.history.List(HistoryFilter{})) == i+1 && nextRun.Equal(start.Add(time.Duration(i+2)*10*time.Second))
+			}, time.Second, 10*time.Millisecond)
+		}
+
+		records := ticker.history.List(HistoryFilter{})
+		assert.True(t, start.Add(20*time.Second).Equal(records[0].ScheduledAt))
+		assert.True(t, start.Add(10*time.Second).Equal(records[1].ScheduledAt))
+		assert.Equal(t, runOutcomeSkipped, records[0].Outcome)
+	})
+
+	t.Run("jitter cancelled", func(t *testing.T) {
+		ticker := &Ticker{}
+		task := &TickerTask{
+			ID:     uuid.New(),
+			Jitter: time.Hour,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := ticker.TaskFunc(ctx, task)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, uint64(0), task.State().RunCount)
+	})
+
+	t.Run("blackout", func(t *testing.T) {
+		windows, err := parseBlackouts("0 * * * * for 1h", nil)
+		assert.NoError(t, err)
+
+		ticker := &Ticker{
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+		task := &TickerTask{
+			ID:        uuid.New(),
+			Blackouts: windows,
+		}
+
+		err = ticker.TaskFunc(context.Background(), task)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), task.skipped)
+		assert.Equal(t, uint64(0), task.State().RunCount)
+	})
+
+	t.Run("provider blackout", func(t *testing.T) {
+		windows, err := parseBlackouts("0 * * * * for 1h", nil)
+		assert.NoError(t, err)
+
+		ticker := &Ticker{
+			blackouts: windows,
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+		task := &TickerTask{
+			ID: uuid.New(),
+		}
+		_, ok := ticker.getBlackout(task, time.Now())
+		assert.True(t, ok)
+
+		task.Critical = true
+		_, ok = ticker.getBlackout(task, time.Now())
+		assert.False(t, ok)
+
+		err = ticker.CatchUpFunc(context.Background(), &TickerTask{ID: uuid.New()}, []time.Time{time.Now()})
+		assert.NoError(t, err)
+	})
+
+	t.Run("records history", func(t *testing.T) {
+		windows, err := parseBlackouts("0 * * * * for 1h", nil)
+		assert.NoError(t, err)
+
+		history, err := NewRunHistory("")
+		assert.NoError(t, err)
+		ticker := &Ticker{
+			history: history,
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+		task := &TickerTask{
+			Component:   "my-component",
+			ID:          uuid.New(),
+			Link:        "default",
+			Blackouts:   windows,
+			HistorySize: 5,
+			key:         "default.my-component",
+		}
+
+		err = ticker.TaskFunc(context.Background(), task)
+		assert.NoError(t, err)
+
+		records := history.List(HistoryFilter{Link: "default"})
+		assert.Len(t, records, 1)
+		assert.Equal(t, "default.my-component", records[0].Key)
+		assert.Equal(t, "my-component", records[0].Component)
+		assert.Equal(t, runOutcomeSkipped, records[0].Outcome)
+		assert.Equal(t, skipReasonBlackout, records[0].Error)
+
+		ticker.deleteHistory(task.key)
+		assert.Empty(t, history.List(HistoryFilter{}))
+	})
+
+	t.Run("records state", func(t *testing.T) {
+		task := TickerTask{
+			ID: uuid.New(),
+		}
+		firedAt := time.Now()
+
+		task.invocation(firedAt)
+		task.complete(firedAt.Add(time.Second), nil)
+		task.complete(firedAt.Add(2*time.Second), errors.New("test error"))
+
+		state := task.State()
+		assert.Equal(t, firedAt, state.LastScheduled)
+		assert.Equal(t, firedAt.Add(time.Second), state.LastSuccess)
+		assert.Equal(t, firedAt.Add(2*time.Second), state.LastFailure)
+		assert.Equal(t, uint64(1), state.RunCount)
+	})
+}
+
+// blockingInvoker is a component which never answers, so every invocation
+// runs until its context is done.
+type blockingInvoker struct {
+	calls atomic.Int32
+}
+
+func (i *blockingInvoker) Invoke(ctx context.Context, _ string, _ string, _ []byte, _ ...wrpc.SubscribePath) (wrpc.IndexWriteCloser, wrpc.IndexReadCloser, error) {
+	i.calls.Add(1)
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestTaskTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		attempts int32
+	}{
+		{
+			name:     "retried",
+			config:   map[string]string{"retry_max": "2", "retry_backoff": "1ms", "retry_on": "timeout"},
+			attempts: 3,
+		},
+		{
+			name:     "not retried",
+			config:   map[string]string{"retry_max": "2", "retry_backoff": "1ms", "retry_on": "transport,task"},
+			attempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, err := newRetryPolicy(tt.config)
+			assert.NoError(t, err)
+
+			invoker := &blockingInvoker{}
+			ticker := &Ticker{
+				invoker: func(string) wrpc.Invoker { return invoker },
+				provider: &provider.WasmcloudProvider{
+					Logger: slog.Default(),
+				},
+			}
+			task := &TickerTask{
+				Component: "my-component",
+				ID:        uuid.New(),
+				Version:   tickerVersionLatest,
+				Retry:     retry,
+				Timeout:   20 * time.Millisecond,
+			}
+
+			start := time.Now()
+			invocation, _ := task.invocation(start)
+			err = ticker.executeTask(context.Background(), task, invocation, false)
+			assert.ErrorIs(t, err, ErrTaskTimeout)
+			assert.ErrorContains(t, err, "after 20ms")
+			assert.Equal(t, tt.attempts, invoker.calls.Load())
+			assert.Equal(t, uint32(tt.attempts), invocation.Attempt)
+			assert.GreaterOrEqual(t, time.Since(start), time.Duration(tt.attempts)*task.Timeout)
+
+			state := task.State()
+			assert.False(t, state.LastFailure.IsZero())
+			assert.True(t, state.LastSuccess.IsZero())
+		})
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		elector *NatsElector
+		stopped bool
+		tasks   []*TickerTask
+		maxAge  time.Duration
+		healthy bool
+		message string
+	}{
+		{
+			name:    "single replica",
+			healthy: true,
+			message: "healthy, 0 jobs",
+		},
+		{
+			name:    "leader unknown",
+			elector: &NatsElector{id: "host-a"},
+			healthy: true,
+			message: "healthy, 0 jobs, leader: unknown",
+		},
+		{
+			name:    "this replica leader",
+			elector: &NatsElector{id: "host-a", leader: "host-a"},
+			healthy: true,
+			message: "healthy, 0 jobs, leader: host-a (this replica)",
+		},
+		{
+			name:    "other replica leader",
+			elector: &NatsElector{id: "host-a", leader: "host-b"},
+			healthy: true,
+			message: "healthy, 0 jobs, leader: host-b",
+		},
+		{
+			name:    "scheduler not started",
+			stopped: true,
+			message: "unhealthy, scheduler not started",
+		},
+		{
+			name: "tasks below threshold",
+			tasks: []*TickerTask{
+				{ID: uuid.New(), key: "default.a", failures: 2},
+				{ID: uuid.New(), key: "default.b", lastSuccess: now.Add(-time.Hour)},
+				{key: "default.c", failures: 5},
+			},
+			healthy: true,
+			message: "healthy, 2 jobs",
+		},
+		{
+			name: "consecutive failures",
+			tasks: []*TickerTask{
+				{ID: uuid.New(), key: "default.a", failures: 3},
+				{ID: uuid.New(), key: "default.b"},
+			},
+			message: "unhealthy, 1 of 2 jobs failing: default.a (3 consecutive failures)",
+		},
+		{
+			name: "last success too old",
+			tasks: []*TickerTask{
+				{ID: uuid.New(), key: "nightly.b", lastSuccess: now.Add(-26 * time.Hour)},
+				{ID: uuid.New(), key: "default.a", failures: 4},
+			},
+			maxAge:  25 * time.Hour,
+			elector: &NatsElector{id: "host-a", leader: "host-a"},
+			message: "unhealthy, 2 of 2 jobs failing: default.a (4 consecutive failures), nightly.b (last success 26h0m0s ago), leader: host-a (this replica)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ticker := Ticker{
+				elector:          test.elector,
+				started:          !test.stopped,
+				taskList:         map[string]*TickerTask{},
+				failureThreshold: healthFailuresDefault,
+				maxSuccessAge:    test.maxAge,
+			}
+			for _, task := range test.tasks {
+				ticker.taskList[task.key] = task
+			}
+
+			h := provider.HealthCheckResponse{}
+			err := json.Unmarshal([]byte(ticker.handleHealthCheck()), &h)
+			assert.NoError(t, err)
+			assert.Equal(t, test.healthy, h.Healthy)
+			assert.Equal(t, test.message, h.Message)
+		})
+	}
+
+	t.Run("failures reset on success", func(t *testing.T) {
+		task := &TickerTask{ID: uuid.New(), key: "default.a"}
+		for range 3 {
+			task.complete(now, errors.New("test error"))
+		}
+		_, ok := task.unhealthy(now, 3, 0)
+		assert.True(t, ok)
+
+		task.complete(now, nil)
+		_, ok = task.unhealthy(now, 3, 0)
+		assert.False(t, ok)
+
+		task.complete(now, errors.New("test error"))
+		_, ok = task.unhealthy(now, 0, 0)
+		assert.False(t, ok)
+	})
+}
+
+func TestTimezone(t *testing.T) {
+	newTicker := func(t *testing.T, now time.Time, config map[string]string) *Ticker {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+
+		config["state_store"] = "none"
+		err = ticker.Configure(config, nil)
+		assert.NoError(t, err)
+
+		// Run every scheduler on a fake clock
+		ticker.options = append(ticker.options, gocron.WithClock(clockwork.NewFakeClockAt(now)))
+		ticker.tasks, err = ticker.newScheduler(ticker.options...)
+		assert.NoError(t, err)
+		t.Cleanup(func() { ticker.Shutdown() })
+		return ticker
+	}
+
+	nextRuns := func(t *testing.T, task *TickerTask, count int) []time.Time {
+		runs, err := task.job.NextRuns(count)
+		assert.NoError(t, err)
+		for i := range runs {
+			runs[i] = runs[i].UTC()
+		}
+		return runs
+	}
+
+	t.Run("daily across spring forward", func(t *testing.T) {
+		ticker := newTicker(t, time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC), map[string]string{
+			"timezone": "UTC",
+		})
+
+		err := ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":     "daily",
+				"at":       "02:30",
+				"timezone": "America/New_York",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		// 02:30 does not exist on the day clocks go forward, so it resolves
+		// using the offset from before the change, i.e. 01:30 EST
+		assert.Equal(t, []time.Time{
+			time.Date(2025, 3, 9, 6, 30, 0, 0, time.UTC),
+			time.Date(2025, 3, 10, 6, 30, 0, 0, time.UTC),
+			time.Date(2025, 3, 11, 6, 30, 0, 0, time.UTC),
+		}, nextRuns(t, ticker.taskList["default.my-id"], 3))
+		assert.Empty(t, ticker.tasks.Jobs())
+		assert.Len(t, ticker.zones["America/New_York"].Jobs(), 1)
+	})
+
+	t.Run("cron across fall back", func(t *testing.T) {
+		ticker := newTicker(t, time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC), map[string]string{
+			"timezone": "UTC",
+		})
+
+		err := ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type": "cron",
+				"cron": "CRON_TZ=America/New_York 30 1 * * *",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		// 01:30 happens twice on the day clocks go back, and cron runs at both
+		assert.Equal(t, []time.Time{
+			time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC),
+			time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC),
+			time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC),
+		}, nextRuns(t, ticker.taskList["default.my-id"], 3))
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+	})
+
+	t.Run("provider default location", func(t *testing.T) {
+		ticker := newTicker(t, time.Date(2025, 3, 29, 12, 0, 0, 0, time.UTC), map[string]string{
+			"timezone": "Europe/London",
+		})
+
+		err := ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type": "daily",
+				"at":   "09:00",
+			},
+		})
+		assert.NoError(t, err)
+		ticker.Start()
+
+		task := ticker.taskList["default.my-id"]
+		assert.Equal(t, "Europe/London", task.Config["timezone"])
+		assert.Equal(t, []time.Time{
+			time.Date(2025, 3, 30, 8, 0, 0, 0, time.UTC),
+			time.Date(2025, 3, 31, 8, 0, 0, 0, time.UTC),
+		}, nextRuns(t, task, 2))
+		assert.Len(t, ticker.tasks.Jobs(), 1)
+		assert.Empty(t, ticker.zones)
+	})
+
+	t.Run("moves between time zones", func(t *testing.T) {
+		ticker := newTicker(t, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), map[string]string{
+			"timezone": "UTC",
+		})
+
+		testLink := provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":     "daily",
+				"at":       "09:00",
+				"timezone": "America/New_York",
+			},
+		}
+		err := ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		ticker.Start()
+
+		testLink.TargetConfig["timezone"] = "Europe/Paris"
+		err = ticker.handlePutTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Empty(t, ticker.zones["America/New_York"].Jobs())
+		assert.Len(t, ticker.zones["Europe/Paris"].Jobs(), 1)
+		assert.Equal(t, []time.Time{
+			time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC),
+		}, nextRuns(t, ticker.taskList["default.my-id"], 1))
+
+		err = ticker.handleDelTargetLink(testLink)
+		assert.NoError(t, err)
+		assert.Empty(t, ticker.zones["Europe/Paris"].Jobs())
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		ticker := newTicker(t, time.Now(), map[string]string{})
+
+		err := ticker.handlePutTargetLink(provider.InterfaceLinkDefinition{
+			Name:     "default",
+			SourceID: "my-id",
+			TargetConfig: map[string]string{
+				"type":     "weekly",
+				"days":     "mon",
+				"at":       "09:00",
+				"timezone": "Mars/Olympus_Mons",
+			},
+		})
+		assert.ErrorIs(t, err, ErrInvalidConfigValue)
+		assert.Empty(t, ticker.taskList)
 	})
 }
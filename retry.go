@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy describes how failed task invocations are retried before the
+// run is declared failed.
+type RetryPolicy struct {
+	MaxRetries  uint32
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	OnTransport bool
+	OnTask      bool
+	OnTimeout   bool
+}
+
+// ShouldRetry reports whether a run which failed on the given attempt
+// (starting at 1) should be invoked again.
+func (r *RetryPolicy) ShouldRetry(attempt uint32, err error) bool {
+	if r == nil || err == nil || attempt > r.MaxRetries {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, ErrTaskError):
+		return r.OnTask
+	case errors.Is(err, ErrTaskTimeout):
+		return r.OnTimeout
+	default:
+		return r.OnTransport
+	}
+}
+
+// Delay returns the time to wait after the given attempt, doubling the
+// backoff for each attempt up to MaxBackoff with equal jitter applied.
+func (r *RetryPolicy) Delay(attempt uint32) time.Duration {
+	delay := r.Backoff
+	for i := uint32(1); i < attempt && delay < r.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxBackoff {
+		delay = r.MaxBackoff
+	}
+
+	if delay <= 1 {
+		return delay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)))
+}
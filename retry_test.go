@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxRetries:  2,
+		OnTransport: true,
+	}
+	transportErr := errors.New("transport error")
+	taskErr := fmt.Errorf("%w: test error", ErrTaskError)
+	timeoutErr := fmt.Errorf("%w: test error", ErrTaskTimeout)
+
+	assert.True(t, policy.ShouldRetry(1, transportErr))
+	assert.True(t, policy.ShouldRetry(2, transportErr))
+	assert.False(t, policy.ShouldRetry(3, transportErr))
+	assert.False(t, policy.ShouldRetry(1, taskErr))
+	assert.False(t, policy.ShouldRetry(1, timeoutErr))
+	assert.False(t, policy.ShouldRetry(1, nil))
+
+	var nilPolicy *RetryPolicy
+	assert.False(t, nilPolicy.ShouldRetry(1, transportErr))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+
+	tests := []struct {
+		attempt uint32
+		max     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		delay := policy.Delay(tt.attempt)
+		assert.GreaterOrEqual(t, delay, tt.max/2, "attempt %d", tt.attempt)
+		assert.LessOrEqual(t, delay, tt.max, "attempt %d", tt.attempt)
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	server "github.com/jamesstocktonj1/ticker-provider/bindings"
+	"github.com/jamesstocktonj1/ticker-provider/bindings/exports/jamesstocktonj1/ticker/scheduler"
+	"go.opentelemetry.io/otel/attribute"
+	wrpc "wrpc.io/go"
+	wrpcnats "wrpc.io/go/nats"
+)
+
+const (
+	sourceIDHeader = "source-id"
+
+	// runtimeMinPeriod is the shortest period of an interval job scheduled by
+	// a component, so a component cannot flood the lattice with invocations.
+	runtimeMinPeriod = time.Second
+)
+
+var (
+	ErrUnknownSource = errors.New("error unable to determine calling component")
+
+	_ server.Handler = (*Ticker)(nil)
+)
+
+func getSourceID(ctx context.Context) (string, error) {
+	header, ok := wrpcnats.HeaderFromContext(ctx)
+	if !ok {
+		return "", ErrUnknownSource
+	}
+
+	sourceID := header.Get(sourceIDHeader)
+	if sourceID == "" {
+		return "", ErrUnknownSource
+	}
+	return sourceID, nil
+}
+
+func (t *Ticker) ScheduleOnce(ctx context.Context, delay string) (*wrpc.Result[string, string], error) {
+	return t.scheduleRuntimeJob(ctx, map[string]string{
+		configTypeKey:  configTypeStartup,
+		delayConfigKey: delay,
+	})
+}
+
+func (t *Ticker) ScheduleInterval(ctx context.Context, period string) (*wrpc.Result[string, string], error) {
+	return t.scheduleRuntimeJob(ctx, map[string]string{
+		configTypeKey:     configTypeInterval,
+		intervalConfigKey: period,
+	})
+}
+
+func (t *Ticker) ScheduleCron(ctx context.Context, cron string, seconds bool) (*wrpc.Result[string, string], error) {
+	return t.scheduleRuntimeJob(ctx, map[string]string{
+		configTypeKey:    configTypeCron,
+		cronConfigKey:    cron,
+		cronSecConfigKey: strconv.FormatBool(seconds),
+	})
+}
+
+func (t *Ticker) Cancel(ctx context.Context, id string) (*wrpc.Result[struct{}, string], error) {
+	ctx, span := tracer.Start(extractTraceHeader(ctx), "Cancel")
+	defer span.End()
+
+	sourceID, err := getSourceID(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return wrpc.Err[struct{}](err.Error()), nil
+	}
+
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		span.RecordError(err)
+		return wrpc.Err[struct{}](err.Error()), nil
+	}
+	span.SetAttributes(
+		attribute.String("id", id),
+		attribute.String("component", sourceID),
+	)
+
+	// Jobs are removed from the scheduler without holding the lock, which
+	// the scheduler needs to report the outcome of a run in progress
+	jobKey := getRuntimeJobKey(sourceID, jobID)
+	t.lock.Lock()
+	task, ok := t.taskList[jobKey]
+	if ok && task.runtime {
+		delete(t.taskList, jobKey)
+	}
+	t.lock.Unlock()
+	if !ok || !task.runtime {
+		return wrpc.Err[struct{}](ErrTickerNotFound.Error()), nil
+	}
+
+	err = t.runtimeScheduler().RemoveJob(task.ID)
+	if err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+		t.lock.Lock()
+		t.taskList[jobKey] = task
+		t.lock.Unlock()
+
+		t.provider.Logger.Error("error: RemoveJob", "error", err, "id", id)
+		span.RecordError(err)
+		return wrpc.Err[struct{}](err.Error()), nil
+	}
+
+	t.deleteHistory(jobKey)
+	t.provider.Logger.Info("runtime job cancelled", "id", id, "component", sourceID)
+	return wrpc.Ok[string](struct{}{}), nil
+}
+
+func (t *Ticker) List(ctx context.Context) ([]*scheduler.JobInfo, error) {
+	ctx, span := tracer.Start(extractTraceHeader(ctx), "List")
+	defer span.End()
+
+	sourceID, err := getSourceID(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	t.lock.RLock()
+	tasks := []*TickerTask{}
+	for _, task := range t.taskList {
+		if task.runtime && task.Component == sourceID {
+			tasks = append(tasks, task)
+		}
+	}
+	t.lock.RUnlock()
+
+	// Next runs are asked of the scheduler without holding the lock
+	jobs := []*scheduler.JobInfo{}
+	for _, task := range tasks {
+		info := &scheduler.JobInfo{
+			Id:           task.ID.String(),
+			ScheduleType: task.Type,
+			Schedule:     getScheduleDescription(task.Config),
+		}
+		if nextRun, ok := task.NextRun(); ok {
+			nextRunMilli := uint64(nextRun.UnixMilli())
+			info.NextRun = &nextRunMilli
+		}
+		jobs = append(jobs, info)
+	}
+	return jobs, nil
+}
+
+// scheduleRuntimeJob creates a job requested by a component at runtime, which
+// is scoped to the calling component and invokes its task export.
+func (t *Ticker) scheduleRuntimeJob(ctx context.Context, config map[string]string) (*wrpc.Result[string, string], error) {
+	ctx, span := tracer.Start(extractTraceHeader(ctx), "scheduleRuntimeJob")
+	defer span.End()
+
+	sourceID, err := getSourceID(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return wrpc.Err[string](err.Error()), nil
+	}
+
+	jobDef, err := newSchedulerJob(config)
+	if err != nil {
+		span.RecordError(err)
+		return wrpc.Err[string](err.Error()), nil
+	}
+
+	err = checkRuntimePeriod(config)
+	if err != nil {
+		span.RecordError(err)
+		return wrpc.Err[string](err.Error()), nil
+	}
+
+	jobID := uuid.New()
+	jobKey := getRuntimeJobKey(sourceID, jobID)
+	jobCtx := &TickerTask{
+		Component: sourceID,
+		ID:        jobID,
+		Type:      config[configTypeKey],
+		Version:   tickerVersionLatest,
+		Config:    config,
+		Timeout:   t.defaultTimeout,
+		key:       jobKey,
+		runtime:   true,
+
+		HistorySize: historySizeDefault,
+	}
+	span.SetAttributes(
+		attribute.String("id", jobID.String()),
+		attribute.String("component", sourceID),
+		attribute.String("type", jobCtx.Type),
+	)
+
+	options := []gocron.JobOption{
+		gocron.WithIdentifier(jobID),
+		gocron.WithName(jobKey),
+		jobCtx.nextRunListener(),
+	}
+	if jobCtx.Type == configTypeStartup {
+		// Remove one-off jobs once they have fired
+		remove := func(id uuid.UUID, _ string) {
+			t.runtimeScheduler().RemoveJob(id)
+			t.forgetTask(jobKey)
+		}
+		options = append(options, gocron.WithEventListeners(
+			gocron.AfterJobRuns(remove),
+			gocron.AfterJobRunsWithError(func(id uuid.UUID, name string, _ error) { remove(id, name) }),
+		))
+	}
+
+	t.lock.Lock()
+	t.taskList[jobKey] = jobCtx
+	t.lock.Unlock()
+
+	job, err := t.runtimeScheduler().NewJob(
+		jobDef,
+		gocron.NewTask(t.TaskFunc, jobCtx),
+		options...,
+	)
+	if err != nil {
+		t.forgetTask(jobKey)
+		t.provider.Logger.Error("error: NewJob", "error", err, "component", sourceID)
+		span.RecordError(err)
+		return wrpc.Err[string](err.Error()), nil
+	}
+	jobCtx.setJob(job)
+
+	t.provider.Logger.Info("runtime job scheduled", "id", jobID.String(), "component", sourceID, "type", jobCtx.Type)
+	return wrpc.Ok[string](jobID.String()), nil
+}
+
+// checkRuntimePeriod rejects interval jobs scheduled by a component with a
+// period shorter than runtimeMinPeriod.
+func checkRuntimePeriod(config map[string]string) error {
+	if config[configTypeKey] != configTypeInterval {
+		return nil
+	}
+
+	period, err := getPeriod(config, intervalConfigKey)
+	if err != nil {
+		return err
+	} else if period < runtimeMinPeriod {
+		return fmt.Errorf("%w: key %s: %s is less than %s", ErrInvalidConfigValue, intervalConfigKey, config[intervalConfigKey], runtimeMinPeriod)
+	}
+	return nil
+}
+
+func (t *Ticker) forgetTask(jobKey string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.taskList, jobKey)
+}
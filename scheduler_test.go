@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gocronmocks "github.com/go-co-op/gocron/mocks/v2"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.wasmcloud.dev/provider"
+	wrpc "wrpc.io/go"
+	wrpcnats "wrpc.io/go/nats"
+)
+
+func sourceContext(sourceID string) context.Context {
+	header := nats.Header{}
+	header.Set(sourceIDHeader, sourceID)
+	return wrpcnats.ContextWithHeader(context.Background(), header)
+}
+
+func TestScheduleInterval(t *testing.T) {
+	t.Run("valid schedule", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		j := gocronmocks.NewMockJob(ctrl)
+		j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().NewJob(
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).Return(j, nil).Times(1)
+
+		res, err := ticker.ScheduleInterval(sourceContext("my-component"), "10s")
+		assert.NoError(t, err)
+		assert.NotNil(t, res.Ok)
+		assert.Nil(t, res.Err)
+
+		jobID, err := uuid.Parse(*res.Ok)
+		assert.NoError(t, err)
+
+		task, ok := ticker.taskList[getRuntimeJobKey("my-component", jobID)]
+		assert.True(t, ok)
+		assert.Equal(t, "my-component", task.Component)
+		assert.Equal(t, "interval", task.Type)
+		assert.True(t, task.runtime)
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		res, err := ticker.ScheduleInterval(context.Background(), "10s")
+		assert.NoError(t, err)
+		assert.Nil(t, res.Ok)
+		assert.Equal(t, ErrUnknownSource.Error(), *res.Err)
+		assert.Empty(t, ticker.taskList)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		res, err := ticker.ScheduleInterval(sourceContext("my-component"), "abcd")
+		assert.NoError(t, err)
+		assert.Nil(t, res.Ok)
+		assert.NotNil(t, res.Err)
+		assert.Empty(t, ticker.taskList)
+	})
+
+	t.Run("period too short", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		for _, period := range []string{"1ns", "500ms"} {
+			res, err := ticker.ScheduleInterval(sourceContext("my-component"), period)
+			assert.NoError(t, err)
+			assert.Nil(t, res.Ok)
+			if assert.NotNil(t, res.Err) {
+				assert.Contains(t, *res.Err, "is less than 1s")
+			}
+		}
+		assert.Empty(t, ticker.taskList)
+	})
+
+	t.Run("new job error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+
+		ticker := Ticker{
+			tasks:    s,
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().NewJob(
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, errors.New("test error")).Times(1)
+
+		res, err := ticker.ScheduleInterval(sourceContext("my-component"), "10s")
+		assert.NoError(t, err)
+		assert.Equal(t, "test error", *res.Err)
+		assert.Empty(t, ticker.taskList)
+	})
+}
+
+func TestScheduleOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := gocronmocks.NewMockScheduler(ctrl)
+	j := gocronmocks.NewMockJob(ctrl)
+	j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+
+	ticker := Ticker{
+		tasks:    s,
+		taskList: make(map[string]*TickerTask),
+		provider: &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		},
+	}
+
+	s.EXPECT().NewJob(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(j, nil).Times(1)
+
+	res, err := ticker.ScheduleOnce(sourceContext("my-component"), "5m")
+	assert.NoError(t, err)
+	assert.NotNil(t, res.Ok)
+	assert.Len(t, ticker.taskList, 1)
+}
+
+func TestScheduleCron(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s := gocronmocks.NewMockScheduler(ctrl)
+	j := gocronmocks.NewMockJob(ctrl)
+	j.EXPECT().NextRun().Return(time.Time{}, nil).AnyTimes()
+
+	ticker := Ticker{
+		tasks:    s,
+		taskList: make(map[string]*TickerTask),
+		provider: &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		},
+	}
+
+	s.EXPECT().NewJob(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(j, nil).Times(1)
+
+	res, err := ticker.ScheduleCron(sourceContext("my-component"), "0 * * * * *", true)
+	assert.NoError(t, err)
+	assert.NotNil(t, res.Ok)
+
+	for _, task := range ticker.taskList {
+		assert.Equal(t, "cron", task.Type)
+		assert.Equal(t, "true", task.Config["seconds"])
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Run("valid cancel", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		jobID := uuid.New()
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				getRuntimeJobKey("my-component", jobID): {
+					Component: "my-component",
+					ID:        jobID,
+					Type:      "interval",
+					runtime:   true,
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		s.EXPECT().RemoveJob(jobID).Return(nil).Times(1)
+
+		res, err := ticker.Cancel(sourceContext("my-component"), jobID.String())
+		assert.NoError(t, err)
+		assert.NotNil(t, res.Ok)
+		assert.Empty(t, ticker.taskList)
+	})
+
+	t.Run("other component", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		jobID := uuid.New()
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				getRuntimeJobKey("my-component", jobID): {
+					Component: "my-component",
+					ID:        jobID,
+					Type:      "interval",
+					runtime:   true,
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		res, err := ticker.Cancel(sourceContext("other-component"), jobID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, ErrTickerNotFound.Error(), *res.Err)
+		assert.Len(t, ticker.taskList, 1)
+	})
+
+	t.Run("link job", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		jobID := uuid.New()
+
+		// A link named after the ID shares the key of a runtime job
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				getRuntimeJobKey("my-component", jobID): {
+					Component: "my-component",
+					ID:        jobID,
+					Link:      jobID.String(),
+					Type:      "interval",
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		res, err := ticker.Cancel(sourceContext("my-component"), jobID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, ErrTickerNotFound.Error(), *res.Err)
+		assert.Len(t, ticker.taskList, 1)
+	})
+
+	t.Run("remove error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := gocronmocks.NewMockScheduler(ctrl)
+		jobID := uuid.New()
+
+		ticker := Ticker{
+			tasks: s,
+			taskList: map[string]*TickerTask{
+				getRuntimeJobKey("my-component", jobID): {
+					Component: "my-component",
+					ID:        jobID,
+					Type:      "interval",
+					runtime:   true,
+				},
+			},
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		// The scheduler can look up tasks while a job is being removed
+		s.EXPECT().RemoveJob(jobID).DoAndReturn(func(id uuid.UUID) error {
+			_, ok := ticker.getTaskByID(id)
+			assert.False(t, ok)
+			return errors.New("test error")
+		}).Times(1)
+
+		res, err := ticker.Cancel(sourceContext("my-component"), jobID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "test error", *res.Err)
+		assert.Len(t, ticker.taskList, 1)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		ticker := Ticker{
+			taskList: make(map[string]*TickerTask),
+			provider: &provider.WasmcloudProvider{
+				Logger: slog.Default(),
+			},
+		}
+
+		res, err := ticker.Cancel(sourceContext("my-component"), "abcd")
+		assert.NoError(t, err)
+		assert.NotNil(t, res.Err)
+	})
+}
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	j := gocronmocks.NewMockJob(ctrl)
+	jobID := uuid.New()
+	nextRun := time.Now().Add(time.Minute)
+
+	ticker := Ticker{
+		taskList: map[string]*TickerTask{
+			getRuntimeJobKey("my-component", jobID): {
+				Component: "my-component",
+				ID:        jobID,
+				Type:      "interval",
+				Config: map[string]string{
+					"type":   "interval",
+					"period": "1m",
+				},
+				runtime: true,
+				job:     j,
+			},
+			getRuntimeJobKey("other-component", uuid.New()): {
+				Component: "other-component",
+				ID:        uuid.New(),
+				Type:      "interval",
+				runtime:   true,
+			},
+			"default.my-component": {
+				Component: "my-component",
+				ID:        uuid.New(),
+				Type:      "cron",
+			},
+		},
+		provider: &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		},
+	}
+
+	j.EXPECT().NextRun().Return(nextRun, nil).Times(1)
+
+	jobs, err := ticker.List(sourceContext("my-component"))
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, jobID.String(), jobs[0].Id)
+	assert.Equal(t, "interval", jobs[0].ScheduleType)
+	assert.Equal(t, "1m", jobs[0].Schedule)
+	assert.Equal(t, uint64(nextRun.UnixMilli()), *jobs[0].NextRun)
+}
+
+// countingInvoker is a component which fails every invocation straight away,
+// counting how many it receives.
+type countingInvoker struct {
+	calls atomic.Int32
+}
+
+func (i *countingInvoker) Invoke(context.Context, string, string, []byte, ...wrpc.SubscribePath) (wrpc.IndexWriteCloser, wrpc.IndexReadCloser, error) {
+	i.calls.Add(1)
+	return nil, nil, errors.New("test error")
+}
+
+func TestRuntimeJobLeaderMode(t *testing.T) {
+	nc := runNatsServer(t)
+	config := map[string]string{
+		"distributed":   "leader",
+		"leader_bucket": "test_leader",
+		"leader_lease":  "2s",
+		"state_store":   "none",
+	}
+	newReplica := func() *Ticker {
+		ticker, err := CreateTicker()
+		assert.NoError(t, err)
+		ticker.provider = &provider.WasmcloudProvider{
+			Logger: slog.Default(),
+		}
+		assert.NoError(t, ticker.Configure(config, nc))
+		return ticker
+	}
+
+	leader := newReplica()
+	assert.NoError(t, leader.Start())
+	defer leader.Shutdown()
+	assert.Eventually(t, func() bool {
+		return leader.elector.IsLeader(context.Background()) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	follower := newReplica()
+	invoker := &countingInvoker{}
+	follower.invoker = func(string) wrpc.Invoker { return invoker }
+	assert.NoError(t, follower.Start())
+	defer follower.Shutdown()
+	assert.ErrorIs(t, follower.elector.IsLeader(context.Background()), ErrNotLeader)
+
+	// Only the follower has the job, so it fires there despite not leading
+	res, err := follower.ScheduleInterval(sourceContext("my-component"), "1s")
+	assert.NoError(t, err)
+	assert.NotNil(t, res.Ok)
+	assert.Eventually(t, func() bool {
+		return invoker.calls.Load() > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	jobID, err := uuid.Parse(*res.Ok)
+	assert.NoError(t, err)
+	cancelled, err := follower.Cancel(sourceContext("my-component"), jobID.String())
+	assert.NoError(t, err)
+	assert.Nil(t, cancelled.Err)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	solarEventSunrise   = "sunrise"
+	solarEventSunset    = "sunset"
+	solarEventDawn      = "dawn"
+	solarEventDusk      = "dusk"
+	solarEventSolarNoon = "solar-noon"
+
+	// Altitudes of the sun's centre at each event, in degrees, allowing for
+	// refraction and the size of the sun at sunrise and sunset
+	sunriseAltitude  = -0.833
+	twilightAltitude = -6.0
+
+	julianUnixEpoch = 2440587.5
+	julian2000      = 2451545.0
+	earthTilt       = 23.4397
+
+	// maxSolarSearchDays bounds the search for the next event, which does
+	// not happen for months at a time near the poles
+	maxSolarSearchDays = 400
+)
+
+// SolarSchedule runs at a solar event, such as sunset, at a site on earth,
+// moved by an offset. Times are computed with the sunrise equation, which is
+// accurate to around a minute away from the poles.
+type SolarSchedule struct {
+	latitude  float64
+	longitude float64
+	event     string
+	offset    time.Duration
+}
+
+// Next returns the first run after the given time, or the zero time if the
+// event does not happen within the search period.
+func (s *SolarSchedule) Next(from time.Time) time.Time {
+	start := from.UTC().Add(-s.offset)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 12, 0, 0, 0, time.UTC)
+	for i := -1; i <= maxSolarSearchDays; i++ {
+		event, ok := s.eventOn(day.AddDate(0, 0, i))
+		if !ok {
+			continue
+		}
+
+		run := event.Add(s.offset)
+		if run.After(from) {
+			return run
+		}
+	}
+	return time.Time{}
+}
+
+// eventOn returns the time of the event on the solar day closest to the
+// given time, reporting false if the sun does not reach the event's altitude
+// that day.
+func (s *SolarSchedule) eventOn(day time.Time) (time.Time, bool) {
+	julianDay := julianUnixEpoch + float64(day.Unix())/86400
+	n := math.Round(julianDay - julian2000 + 0.0008)
+
+	// Mean solar time, anomaly and equation of the centre
+	meanTime := n - s.longitude/360
+	anomaly := math.Mod(357.5291+0.98560028*meanTime, 360)
+	centre := 1.9148*sinDeg(anomaly) + 0.02*sinDeg(2*anomaly) + 0.0003*sinDeg(3*anomaly)
+	eclipticLongitude := math.Mod(anomaly+centre+180+102.9372, 360)
+
+	transit := julian2000 + meanTime + 0.0053*sinDeg(anomaly) - 0.0069*sinDeg(2*eclipticLongitude)
+	if s.event == solarEventSolarNoon {
+		return julianTime(transit), true
+	}
+
+	altitude := sunriseAltitude
+	if s.event == solarEventDawn || s.event == solarEventDusk {
+		altitude = twilightAltitude
+	}
+
+	sinDeclination := sinDeg(eclipticLongitude) * sinDeg(earthTilt)
+	cosDeclination := math.Cos(math.Asin(sinDeclination))
+	cosHourAngle := (sinDeg(altitude) - sinDeg(s.latitude)*sinDeclination) / (cosDeg(s.latitude) * cosDeclination)
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+
+	switch s.event {
+	case solarEventSunrise, solarEventDawn:
+		return julianTime(transit - hourAngle/360), true
+	default:
+		return julianTime(transit + hourAngle/360), true
+	}
+}
+
+// julianTime converts a Julian date to a time, to the nearest second.
+func julianTime(julianDay float64) time.Time {
+	seconds := math.Round((julianDay - julianUnixEpoch) * 86400)
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+func sinDeg(degrees float64) float64 {
+	return math.Sin(degrees * math.Pi / 180)
+}
+
+func cosDeg(degrees float64) float64 {
+	return math.Cos(degrees * math.Pi / 180)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolarScheduleNext(t *testing.T) {
+	london := &SolarSchedule{latitude: 51.5074, longitude: -0.1278}
+	newYork := &SolarSchedule{latitude: 40.7128, longitude: -74.006}
+	sydney := &SolarSchedule{latitude: -33.8688, longitude: 151.2093}
+
+	// Expected times are from published almanac tables, to the minute
+	tests := []struct {
+		name     string
+		site     *SolarSchedule
+		event    string
+		offset   time.Duration
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "london sunrise at midsummer",
+			site:     london,
+			event:    solarEventSunrise,
+			from:     time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 6, 21, 3, 43, 0, 0, time.UTC),
+		},
+		{
+			name:     "london sunset at midsummer",
+			site:     london,
+			event:    solarEventSunset,
+			from:     time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 6, 21, 20, 21, 0, 0, time.UTC),
+		},
+		{
+			name:     "london solar noon",
+			site:     london,
+			event:    solarEventSolarNoon,
+			from:     time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 6, 21, 12, 2, 0, 0, time.UTC),
+		},
+		{
+			name:     "new york dawn at midwinter",
+			site:     newYork,
+			event:    solarEventDawn,
+			from:     time.Date(2026, 12, 21, 5, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 12, 21, 11, 46, 0, 0, time.UTC),
+		},
+		{
+			name:     "new york dusk at midwinter",
+			site:     newYork,
+			event:    solarEventDusk,
+			from:     time.Date(2026, 12, 21, 5, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 12, 21, 22, 3, 0, 0, time.UTC),
+		},
+		{
+			name:     "sydney sunrise on the previous utc day",
+			site:     sydney,
+			event:    solarEventSunrise,
+			from:     time.Date(2025, 12, 31, 13, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 12, 31, 18, 47, 0, 0, time.UTC),
+		},
+		{
+			name:     "after today's event",
+			site:     london,
+			event:    solarEventSunset,
+			from:     time.Date(2026, 6, 21, 21, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 6, 22, 20, 21, 0, 0, time.UTC),
+		},
+		{
+			name:     "offset after sunset",
+			site:     london,
+			event:    solarEventSunset,
+			offset:   30 * time.Minute,
+			from:     time.Date(2026, 6, 21, 20, 30, 0, 0, time.UTC),
+			expected: time.Date(2026, 6, 21, 20, 51, 0, 0, time.UTC),
+		},
+		{
+			name:     "offset before sunrise",
+			site:     london,
+			event:    solarEventSunrise,
+			offset:   -time.Hour,
+			from:     time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 6, 21, 2, 43, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule := &SolarSchedule{
+				latitude:  test.site.latitude,
+				longitude: test.site.longitude,
+				event:     test.event,
+				offset:    test.offset,
+			}
+			assert.WithinDuration(t, test.expected, schedule.Next(test.from), time.Minute)
+		})
+	}
+
+	t.Run("polar night", func(t *testing.T) {
+		tromso := &SolarSchedule{latitude: 69.6492, longitude: 18.9553, event: solarEventSunrise}
+		next := tromso.Next(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC))
+		assert.True(t, next.After(time.Date(2027, 1, 10, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, next.Before(time.Date(2027, 1, 20, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("midnight sun", func(t *testing.T) {
+		svalbard := &SolarSchedule{latitude: 78.2232, longitude: 15.6267, event: solarEventSunset}
+		next := svalbard.Next(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+		assert.True(t, next.After(time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, next.Before(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)))
+	})
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+var (
+	_ StateStore = (*FileStore)(nil)
+	_ StateStore = (*NatsStore)(nil)
+)
+
+// TaskState is the state of a link's job which is kept across provider
+// restarts.
+type TaskState struct {
+	LastScheduled time.Time `json:"last_scheduled"`
+	LastSuccess   time.Time `json:"last_success"`
+	LastFailure   time.Time `json:"last_failure"`
+	RunCount      uint64    `json:"run_count"`
+	Paused        bool      `json:"paused,omitempty"`
+	ConfigHash    string    `json:"config_hash,omitempty"`
+}
+
+const (
+	// Changes to the state file are written at most this often
+	stateFlushDelay = time.Second
+)
+
+// StateStore persists the state of each link's job, keyed by its job key.
+type StateStore interface {
+	Load(ctx context.Context, key string) (TaskState, bool, error)
+	Save(ctx context.Context, key string, state TaskState) error
+	Delete(ctx context.Context, key string) error
+	Close() error
+}
+
+// FileStore is a StateStore which keeps the state of every job in a single
+// JSON file. The file is written in the background shortly after a change,
+// so runs are not held up by it, and when the store is closed.
+type FileStore struct {
+	path   string
+	mu     sync.Mutex
+	states map[string]TaskState
+	timer  *time.Timer
+	err    error
+
+	// flushMu orders writes of the file, so an older copy of the states
+	// never replaces a newer one
+	flushMu sync.Mutex
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:   path,
+		states: make(map[string]TaskState),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &s.states)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStore) Load(_ context.Context, key string) (TaskState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	return state, ok, nil
+}
+
+func (s *FileStore) Save(_ context.Context, key string, state TaskState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[key] = state
+	return s.changed()
+}
+
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.states[key]; !ok {
+		return nil
+	}
+	delete(s.states, key)
+	return s.changed()
+}
+
+// changed schedules the states to be written to the file, returning the
+// error of the last write, if it failed.
+// The store's lock must be held.
+func (s *FileStore) changed() error {
+	if s.timer == nil {
+		s.timer = time.AfterFunc(stateFlushDelay, func() {
+			err := s.flush()
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+		})
+	}
+
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// Close writes any changes which are still to be written, once any write in
+// progress has finished.
+func (s *FileStore) Close() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.timer != nil
+	if pending {
+		s.timer.Stop()
+	}
+	s.mu.Unlock()
+	if !pending {
+		return nil
+	}
+	return s.write()
+}
+
+func (s *FileStore) flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	return s.write()
+}
+
+// write writes the states to a temporary file and renames it over the store,
+// so a crash mid-write does not leave the store corrupt.
+// The store's flush lock must be held.
+func (s *FileStore) write() error {
+	s.mu.Lock()
+	s.timer = nil
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// NatsStore is a StateStore backed by a NATS JetStream key-value bucket, so
+// the state is shared by every replica of the provider. Keys are prefixed
+// with a scope, so providers sharing a bucket keep their own state.
+type NatsStore struct {
+	kv    jetstream.KeyValue
+	scope string
+}
+
+// NewNatsStore creates the state bucket if it does not exist.
+func NewNatsStore(ctx context.Context, nc *nats.Conn, bucket, scope string) (*NatsStore, error) {
+	if nc == nil {
+		return nil, ErrNoConnection
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "ticker-provider job state",
+		History:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &NatsStore{
+		kv:    kv,
+		scope: scope,
+	}, nil
+}
+
+func (s *NatsStore) key(key string) string {
+	return getKVKey(s.scope + "." + key)
+}
+
+func (s *NatsStore) Load(ctx context.Context, key string) (TaskState, bool, error) {
+	state := TaskState{}
+	entry, err := s.kv.Get(ctx, s.key(key))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return state, false, nil
+	} else if err != nil {
+		return state, false, err
+	}
+
+	err = json.Unmarshal(entry.Value(), &state)
+	if err != nil {
+		return state, false, err
+	}
+	return state, true, nil
+}
+
+func (s *NatsStore) Save(ctx context.Context, key string, state TaskState) error {
+	data, err := json.Marshal(&state)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.kv.Put(ctx, s.key(key), data)
+	return err
+}
+
+func (s *NatsStore) Delete(ctx context.Context, key string) error {
+	err := s.kv.Delete(ctx, s.key(key))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *NatsStore) Close() error {
+	return nil
+}
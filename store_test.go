@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ticker", "state.json")
+	state := TaskState{
+		LastScheduled: time.Now().Add(-time.Minute).UTC(),
+		LastSuccess:   time.Now().UTC(),
+		RunCount:      3,
+	}
+
+	store, err := NewFileStore(path)
+	assert.NoError(t, err)
+
+	_, ok, err := store.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	err = store.Save(ctx, "default.my-component", state)
+	assert.NoError(t, err)
+
+	// Changes are written in the background
+	assert.NoFileExists(t, path)
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 5*stateFlushDelay, 10*time.Millisecond)
+
+	// State is read back by a new store, as after a restart
+	store, err = NewFileStore(path)
+	assert.NoError(t, err)
+
+	loaded, ok, err := store.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, state.LastScheduled.Equal(loaded.LastScheduled))
+	assert.True(t, state.LastSuccess.Equal(loaded.LastSuccess))
+	assert.True(t, loaded.LastFailure.IsZero())
+	assert.Equal(t, uint64(3), loaded.RunCount)
+
+	err = store.Delete(ctx, "default.my-component")
+	assert.NoError(t, err)
+
+	// Closing the store writes changes which are still to be written
+	err = store.Close()
+	assert.NoError(t, err)
+
+	store, err = NewFileStore(path)
+	assert.NoError(t, err)
+
+	_, ok, err = store.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestNatsStore(t *testing.T) {
+	nc := runNatsServer(t)
+	ctx := context.Background()
+	state := TaskState{
+		LastScheduled: time.Now().UTC(),
+		LastFailure:   time.Now().UTC(),
+		RunCount:      7,
+	}
+
+	store, err := NewNatsStore(ctx, nc, "test_state", "default.VPROVIDER")
+	assert.NoError(t, err)
+
+	_, ok, err := store.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	err = store.Save(ctx, "default.my-component", state)
+	assert.NoError(t, err)
+
+	loaded, ok, err := store.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, state.LastFailure.Equal(loaded.LastFailure))
+	assert.Equal(t, uint64(7), loaded.RunCount)
+
+	err = store.Delete(ctx, "default.my-component")
+	assert.NoError(t, err)
+
+	_, ok, err = store.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Providers sharing the bucket keep their own state
+	err = store.Save(ctx, "default.my-component", state)
+	assert.NoError(t, err)
+
+	other, err := NewNatsStore(ctx, nc, "test_state", "staging.VPROVIDER")
+	assert.NoError(t, err)
+
+	_, ok, err = other.Load(ctx, "default.my-component")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = NewNatsStore(ctx, nil, "test_state", "default.VPROVIDER")
+	assert.ErrorIs(t, err, ErrNoConnection)
+}
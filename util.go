@@ -2,13 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
+	"github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
 	"go.opentelemetry.io/otel"
 	"go.wasmcloud.dev/provider"
 	wrpcnats "wrpc.io/go/nats"
@@ -22,28 +37,350 @@ const (
 	configTypeInterval = "interval"
 	configTypeCron     = "cron"
 	configTypeStartup  = "startup"
+	configTypeDaily    = "daily"
+	configTypeWeekly   = "weekly"
+	configTypeMonthly  = "monthly"
+	configTypeRandom   = "random"
+	configTypeOnce     = "once"
+	configTypeRRule    = "rrule"
+	configTypeSolar    = "solar"
+
+	// Schedules Config
+	schedulesConfigKey = "schedules"
+	scheduleKeyPrefix  = "schedule."
 
 	// Interval Config
 	intervalConfigKey = "period"
 
+	// Once Config
+	timesConfigKey = "times"
+
+	// RRule Config
+	rruleConfigKey   = "rrule"
+	dtstartConfigKey = "dtstart"
+	exdateConfigKey  = "exdate"
+
+	// Solar Config
+	latitudeConfigKey  = "latitude"
+	longitudeConfigKey = "longitude"
+	eventConfigKey     = "event"
+	offsetConfigKey    = "offset"
+
+	// Random Config
+	minPeriodConfigKey = "min_period"
+	maxPeriodConfigKey = "max_period"
+
+	// Active Window Config
+	startAtConfigKey = "start_at"
+	endAtConfigKey   = "end_at"
+	maxRunsConfigKey = "max_runs"
+
+	// Business Calendar Config
+	calendarConfigKey     = "calendar"
+	calendarModeConfigKey = "calendar_mode"
+	businessDaysConfigKey = "business_days"
+
+	calendarModeDefault = calendarModeSkip
+	businessDaysDefault = "mon,tue,wed,thu,fri"
+
+	calendarModeSkip     = "skip"
+	calendarModeNext     = "next-business-day"
+	calendarModePrevious = "previous-business-day"
+
+	// Computed Schedules
+	computedRunsMax     = 1000
+	computedRunsHorizon = 366 * 24 * time.Hour
+
+	// Blackout Config
+	blackoutConfigKey = "blackout"
+	criticalConfigKey = "critical"
+
+	// Jitter Config
+	jitterConfigKey = "jitter"
+
 	// Cron Config
 	cronConfigKey    = "cron"
 	cronSecConfigKey = "seconds"
 
 	// Start Up Config
 	delayConfigKey = "delay"
+
+	// Calendar Config
+	atConfigKey          = "at"
+	daysConfigKey        = "days"
+	daysOfMonthConfigKey = "days_of_month"
+	everyConfigKey       = "every"
+
+	// Timezone Config
+	timezoneConfigKey = "timezone"
+
+	// Interface Version Config
+	versionConfigKey     = "version"
+	versionConfigDefault = tickerVersionLegacy
+
+	tickerVersionLegacy = "0.1.0"
+	tickerVersionLatest = "0.2.0"
+
+	// Retry Config
+	retryMaxConfigKey        = "retry_max"
+	retryBackoffConfigKey    = "retry_backoff"
+	retryMaxBackoffConfigKey = "retry_max_backoff"
+	retryOnConfigKey         = "retry_on"
+
+	retryBackoffDefault    = time.Second
+	retryMaxBackoffDefault = time.Minute
+	retryOnDefault         = retryOnTransport + "," + retryOnTask + "," + retryOnTimeout
+
+	retryOnTransport = "transport"
+	retryOnTask      = "task"
+	retryOnTimeout   = "timeout"
+
+	// Overlap Config
+	overlapConfigKey     = "overlap"
+	overlapConfigDefault = overlapAllow
+
+	overlapAllow = "allow"
+	overlapSkip  = "skip"
+	overlapQueue = "queue"
+
+	// Misfire Config
+	misfireConfigKey     = "misfire"
+	misfireConfigDefault = misfireSkip
+	misfireMaxConfigKey  = "misfire_max"
+	misfireMaxDefault    = 10
+
+	misfireSkip    = "skip"
+	misfireRunOnce = "run-once"
+	misfireRunAll  = "run-all"
+
+	// Timeout Config
+	timeoutConfigKey        = "timeout"
+	defaultTimeoutConfigKey = "default_timeout"
+	timeoutDefault          = 30 * time.Second
+
+	// Distributed Config
+	distributedConfigKey     = "distributed"
+	distributedConfigDefault = distributedNone
+
+	distributedNone   = "none"
+	distributedLock   = "lock"
+	distributedLeader = "leader"
+
+	lockBucketConfigKey  = "lock_bucket"
+	lockTTLConfigKey     = "lock_ttl"
+	lockMinHoldConfigKey = "lock_min_hold"
+
+	lockBucketDefault  = "ticker_locks"
+	lockTTLDefault     = time.Minute
+	lockMinHoldDefault = time.Second
+
+	leaderBucketConfigKey = "leader_bucket"
+	leaderLeaseConfigKey  = "leader_lease"
+
+	leaderBucketDefault = "ticker_leader"
+	leaderLeaseDefault  = 10 * time.Second
+
+	// State Config
+	stateStoreConfigKey     = "state_store"
+	stateStoreConfigDefault = stateStoreFile
+
+	stateStoreNone = "none"
+	stateStoreFile = "file"
+	stateStoreNats = "nats"
+
+	stateFileConfigKey   = "state_file"
+	stateBucketConfigKey = "state_bucket"
+
+	stateBucketDefault = "ticker_state"
+
+	// Metrics Config
+	metricsAddressConfigKey = "metrics_address"
+
+	// Health Config
+	healthFailuresConfigKey   = "health_failure_threshold"
+	healthSuccessAgeConfigKey = "health_max_success_age"
+
+	healthFailuresDefault = 3
+
+	// History Config
+	historySizeConfigKey = "history_size"
+	historyFileConfigKey = "history_file"
+
+	historySizeDefault = 20
+	historySizeMax     = 10000
 )
 
 var (
-	ErrInvalidJobType = errors.New("invalid config \"type\" specified")
+	weekdayNames = map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday,
+		"tue": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday,
+		"thu": time.Thursday, "thursday": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday,
+	}
+
+	scheduleNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	ErrInvalidJobType  = errors.New("invalid config \"type\" specified")
+	ErrInvalidVersion  = errors.New("invalid config \"version\" specified")
+	ErrInvalidRetryOn  = errors.New("invalid config \"retry_on\" specified")
+	ErrInvalidOverlap  = errors.New("invalid config \"overlap\" specified")
+	ErrInvalidMisfire  = errors.New("invalid config \"misfire\" specified")
+	ErrInvalidSchedule = errors.New("invalid config \"schedules\" specified")
+	ErrInvalidMode     = errors.New("invalid config \"distributed\" specified")
+	ErrInvalidStore    = errors.New("invalid config \"state_store\" specified")
 
 	ErrMissingConfigValue = errors.New("missing config value")
+	ErrInvalidConfigValue = errors.New("invalid config value")
+	ErrInvalidLease       = errors.New("lease must be at least 1s")
+	ErrTimeInPast         = errors.New("time is in the past")
 )
 
 func getJobKey(link provider.InterfaceLinkDefinition) string {
 	return fmt.Sprintf("%s.%s", link.Name, link.SourceID)
 }
 
+// getScheduleKey returns the key of a named schedule of a link, which is the
+// link's job key for a link with a single unnamed schedule.
+func getScheduleKey(link provider.InterfaceLinkDefinition, name string) string {
+	if name == "" {
+		return getJobKey(link)
+	}
+	return fmt.Sprintf("%s/%s", getJobKey(link), name)
+}
+
+func getRuntimeJobKey(sourceID string, id uuid.UUID) string {
+	return fmt.Sprintf("%s.%s", id.String(), sourceID)
+}
+
+// getLinkSchedules splits a link config into the config of each of its named
+// schedules, given either as a JSON object in `schedules` or as
+// `schedule.<name>.<key>` keys. Keys outside of a named schedule are shared
+// by every schedule. A link without named schedules has a single schedule
+// with an empty name.
+func getLinkSchedules(config map[string]string) (map[string]map[string]string, error) {
+	shared := map[string]string{}
+	named := map[string]map[string]string{}
+	for key, value := range config {
+		if key == schedulesConfigKey {
+			continue
+		}
+
+		scheduleKey, ok := strings.CutPrefix(key, scheduleKeyPrefix)
+		if !ok {
+			shared[key] = value
+			continue
+		}
+
+		name, field, ok := strings.Cut(scheduleKey, ".")
+		if !ok || field == "" || !scheduleNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: key %s", ErrInvalidSchedule, key)
+		}
+		if _, ok := named[name]; !ok {
+			named[name] = map[string]string{}
+		}
+		named[name][field] = value
+	}
+
+	if schedulesConfig, ok := config[schedulesConfigKey]; ok {
+		entries := map[string]map[string]any{}
+		decoder := json.NewDecoder(strings.NewReader(schedulesConfig))
+		decoder.UseNumber()
+		err := decoder.Decode(&entries)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+		}
+
+		for name, entry := range entries {
+			if !scheduleNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("%w: name %q", ErrInvalidSchedule, name)
+			} else if _, ok := named[name]; ok {
+				return nil, fmt.Errorf("%w: duplicate schedule %s", ErrInvalidSchedule, name)
+			}
+
+			named[name] = map[string]string{}
+			for field, value := range entry {
+				switch value.(type) {
+				case string, json.Number, bool:
+					named[name][field] = fmt.Sprint(value)
+				default:
+					return nil, fmt.Errorf("%w: key %s.%s: must be a string, number or bool", ErrInvalidConfigValue, name, field)
+				}
+			}
+		}
+	}
+
+	if len(named) == 0 {
+		return map[string]map[string]string{"": shared}, nil
+	}
+
+	schedules := map[string]map[string]string{}
+	for name, entry := range named {
+		schedule := maps.Clone(shared)
+		maps.Copy(schedule, entry)
+		schedules[name] = schedule
+	}
+	return schedules, nil
+}
+
+// getChangedKeys returns the config keys which differ between two link
+// configs, in sorted order.
+func getChangedKeys(previous, current map[string]string) []string {
+	changed := []string{}
+	for key, value := range current {
+		if previousValue, ok := previous[key]; !ok || previousValue != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	slices.Sort(changed)
+	return changed
+}
+
+// getConfigHash returns a hash of a link config, which is stored in place of
+// the config itself as link configs may hold secrets.
+func getConfigHash(config map[string]string) string {
+	hash := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		fmt.Fprintf(hash, "%q=%q\n", key, config[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func getScheduleDescription(config map[string]string) string {
+	switch config[configTypeKey] {
+	case configTypeInterval:
+		return config[intervalConfigKey]
+	case configTypeCron:
+		return config[cronConfigKey]
+	case configTypeStartup:
+		return config[delayConfigKey]
+	case configTypeDaily:
+		return config[atConfigKey]
+	case configTypeWeekly:
+		return fmt.Sprintf("%s %s", config[daysConfigKey], config[atConfigKey])
+	case configTypeMonthly:
+		return fmt.Sprintf("%s %s", config[daysOfMonthConfigKey], config[atConfigKey])
+	case configTypeRandom:
+		return fmt.Sprintf("%s-%s", config[minPeriodConfigKey], config[maxPeriodConfigKey])
+	case configTypeOnce:
+		return config[timesConfigKey]
+	case configTypeRRule:
+		return config[rruleConfigKey]
+	case configTypeSolar:
+		return strings.TrimSpace(fmt.Sprintf("%s %s", config[eventConfigKey], config[offsetConfigKey]))
+	default:
+		return ""
+	}
+}
+
 func newSchedulerJob(config map[string]string) (gocron.JobDefinition, error) {
 	if _, ok := config[configTypeKey]; !ok {
 		config[configTypeKey] = configTypeDefault
@@ -56,11 +393,44 @@ func newSchedulerJob(config map[string]string) (gocron.JobDefinition, error) {
 		return newCronJob(config)
 	case configTypeStartup:
 		return newStartupJob(config)
+	case configTypeDaily:
+		return newDailyJob(config)
+	case configTypeWeekly:
+		return newWeeklyJob(config)
+	case configTypeMonthly:
+		return newMonthlyJob(config)
+	case configTypeRRule, configTypeSolar:
+		return newComputedJob(config)
+	case configTypeRandom:
+		return newRandomJob(config)
+	case configTypeOnce:
+		return newOnceJob(config)
 	default:
 		return nil, ErrInvalidJobType
 	}
 }
 
+func newSchedulerOptions(config map[string]string) ([]gocron.JobOption, error) {
+	options := []gocron.JobOption{}
+
+	overlap, ok := config[overlapConfigKey]
+	if !ok {
+		overlap = overlapConfigDefault
+	}
+
+	switch overlap {
+	case overlapAllow:
+	case overlapSkip:
+		options = append(options, gocron.WithSingletonMode(gocron.LimitModeReschedule))
+	case overlapQueue:
+		options = append(options, gocron.WithSingletonMode(gocron.LimitModeWait))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOverlap, overlap)
+	}
+
+	return options, nil
+}
+
 func newIntervalJob(config map[string]string) (gocron.JobDefinition, error) {
 	timeIntervalConfig, ok := config[intervalConfigKey]
 	if !ok {
@@ -75,10 +445,138 @@ func newIntervalJob(config map[string]string) (gocron.JobDefinition, error) {
 	return gocron.DurationJob(timeInterval), nil
 }
 
-func newCronJob(config map[string]string) (gocron.JobDefinition, error) {
-	cronConfig, ok := config[cronConfigKey]
+func newRandomJob(config map[string]string) (gocron.JobDefinition, error) {
+	minPeriod, err := getPeriod(config, minPeriodConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPeriod, err := getPeriod(config, maxPeriodConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxPeriod < minPeriod {
+		return nil, fmt.Errorf("%w: key %s: %s is less than %s", ErrInvalidConfigValue, maxPeriodConfigKey, config[maxPeriodConfigKey], minPeriodConfigKey)
+	}
+
+	return gocron.DurationRandomJob(minPeriod, maxPeriod), nil
+}
+
+// getPeriod parses a required, positive duration.
+func getPeriod(config map[string]string, key string) (time.Duration, error) {
+	periodConfig, ok := config[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: key %s", ErrMissingConfigValue, key)
+	}
+
+	period, err := time.ParseDuration(periodConfig)
+	if err != nil || period <= 0 {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, key, periodConfig)
+	}
+	return period, nil
+}
+
+// getActiveWindow parses the RFC3339 times a job starts and ends at, either
+// of which may be zero if not set.
+func getActiveWindow(config map[string]string) (time.Time, time.Time, error) {
+	startAt, err := getOptionalTime(config, startAtConfigKey)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	endAt, err := getOptionalTime(config, endAtConfigKey)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !startAt.IsZero() && !endAt.IsZero() && !endAt.After(startAt) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: key %s: %s is not after %s", ErrInvalidConfigValue, endAtConfigKey, config[endAtConfigKey], startAtConfigKey)
+	}
+	return startAt, endAt, nil
+}
+
+func getOptionalTime(config map[string]string, key string) (time.Time, error) {
+	timeConfig, ok := config[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	at, err := time.Parse(time.RFC3339, timeConfig)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, key, timeConfig)
+	}
+	return at, nil
+}
+
+// getMaxRuns returns how many times a job runs before it is finished, which
+// defaults to 0 for no limit.
+func getMaxRuns(config map[string]string) (uint64, error) {
+	maxRunsConfig, ok := config[maxRunsConfigKey]
+	if !ok {
+		return 0, nil
+	}
+
+	maxRuns, err := strconv.ParseUint(maxRunsConfig, 10, 32)
+	if err != nil || maxRuns == 0 {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, maxRunsConfigKey, maxRunsConfig)
+	}
+	return maxRuns, nil
+}
+
+// getBlackouts parses the blackout windows in which runs are skipped, with
+// cron windows evaluated in the configured time zone.
+func getBlackouts(config map[string]string) ([]*BlackoutWindow, error) {
+	blackoutConfig, ok := config[blackoutConfigKey]
+	if !ok {
+		return nil, nil
+	}
+
+	location, err := getLocation(config)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := parseBlackouts(blackoutConfig, location)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", blackoutConfigKey, err)
+	}
+	return windows, nil
+}
+
+// getJitter returns the most each run of an interval or cron job is delayed
+// by to spread load, which defaults to none.
+func getJitter(config map[string]string) (time.Duration, error) {
+	jitterConfig, ok := config[jitterConfigKey]
 	if !ok {
-		return nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, cronConfigKey)
+		return 0, nil
+	}
+
+	switch config[configTypeKey] {
+	case configTypeInterval, configTypeCron:
+	default:
+		return 0, fmt.Errorf("%w: key %s: not supported by type %s", ErrInvalidConfigValue, jitterConfigKey, config[configTypeKey])
+	}
+
+	jitter, err := time.ParseDuration(jitterConfig)
+	if err != nil || jitter < 0 {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, jitterConfigKey, jitterConfig)
+	}
+
+	// Runs of an interval job would pile up if delayed by a whole period
+	if config[configTypeKey] == configTypeInterval {
+		period, err := time.ParseDuration(config[intervalConfigKey])
+		if err == nil && jitter >= period {
+			return 0, fmt.Errorf("%w: key %s: %s must be less than %s", ErrInvalidConfigValue, jitterConfigKey, jitterConfig, intervalConfigKey)
+		}
+	}
+	return jitter, nil
+}
+
+func newCronJob(config map[string]string) (gocron.JobDefinition, error) {
+	cronConfig, err := getCronSpec(config)
+	if err != nil {
+		return nil, err
 	}
 
 	cronSeconds := false
@@ -105,8 +603,994 @@ func newStartupJob(config map[string]string) (gocron.JobDefinition, error) {
 	), nil
 }
 
-func injectTraceHeader(_ctx context.Context) context.Context {
-	carrier := nats.Header{}
-	otel.GetTextMapPropagator().Inject(_ctx, NatsHeaderCarrier(carrier))
-	return wrpcnats.ContextWithHeader(_ctx, carrier)
+func newOnceJob(config map[string]string) (gocron.JobDefinition, error) {
+	future, past, err := getOnceTimes(config, time.Now())
+	if err != nil {
+		return nil, err
+	} else if len(past) > 0 {
+		return nil, fmt.Errorf("%w: key %s: %s: %w", ErrInvalidConfigValue, timesConfigKey, past[0].Format(time.RFC3339), ErrTimeInPast)
+	}
+
+	return gocron.OneTimeJob(gocron.OneTimeJobStartDateTimes(future...)), nil
+}
+
+// newRemainingOnceJob returns a one-time job for the times which are still
+// to come, or nil if they have all passed.
+func newRemainingOnceJob(config map[string]string) (gocron.JobDefinition, error) {
+	future, _, err := getOnceTimes(config, time.Now())
+	if err != nil || len(future) == 0 {
+		return nil, err
+	}
+
+	return gocron.OneTimeJob(gocron.OneTimeJobStartDateTimes(future...)), nil
+}
+
+// getOnceTimes parses a comma separated list of RFC3339 timestamps, split
+// into those after now and those which are not.
+func getOnceTimes(config map[string]string, now time.Time) ([]time.Time, []time.Time, error) {
+	timesConfig, ok := config[timesConfigKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, timesConfigKey)
+	}
+
+	future := []time.Time{}
+	past := []time.Time{}
+	for _, value := range strings.Split(timesConfig, ",") {
+		value = strings.TrimSpace(value)
+
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, timesConfigKey, value)
+		}
+
+		if at.After(now) {
+			future = append(future, at)
+		} else {
+			past = append(past, at)
+		}
+	}
+
+	slices.SortFunc(future, time.Time.Compare)
+	slices.SortFunc(past, time.Time.Compare)
+	return future, past, nil
+}
+
+func newDailyJob(config map[string]string) (gocron.JobDefinition, error) {
+	every, err := getEvery(config)
+	if err != nil {
+		return nil, err
+	}
+
+	atTimes, err := getAtTimes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocron.DailyJob(every, atTimes), nil
+}
+
+func newWeeklyJob(config map[string]string) (gocron.JobDefinition, error) {
+	every, err := getEvery(config)
+	if err != nil {
+		return nil, err
+	}
+
+	weekdays, err := getWeekdays(config, daysConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	atTimes, err := getAtTimes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocron.WeeklyJob(every, weekdays, atTimes), nil
+}
+
+func newMonthlyJob(config map[string]string) (gocron.JobDefinition, error) {
+	every, err := getEvery(config)
+	if err != nil {
+		return nil, err
+	}
+
+	daysOfMonth, err := getDaysOfMonth(config)
+	if err != nil {
+		return nil, err
+	}
+
+	atTimes, err := getAtTimes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return gocron.MonthlyJob(every, daysOfMonth, atTimes), nil
+}
+
+// getCronSpec returns the cron spec of a job, prefixed with the job's time
+// zone if one is set.
+func getCronSpec(config map[string]string) (string, error) {
+	cronConfig, ok := config[cronConfigKey]
+	if !ok {
+		return "", fmt.Errorf("%w: key %s", ErrMissingConfigValue, cronConfigKey)
+	}
+
+	timezone, spec, ok := splitCronTimezone(cronConfig)
+	if ok {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return "", fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, cronConfigKey, timezone)
+		}
+		if timezoneConfig, ok := config[timezoneConfigKey]; ok && timezoneConfig != timezone {
+			return "", fmt.Errorf("%w: key %s: %s does not match cron time zone %s", ErrInvalidConfigValue, timezoneConfigKey, timezoneConfig, timezone)
+		}
+		return fmt.Sprintf("CRON_TZ=%s %s", timezone, spec), nil
+	}
+
+	location, err := getLocation(config)
+	if err != nil || location == nil {
+		return cronConfig, err
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", location, cronConfig), nil
+}
+
+// splitCronTimezone splits a CRON_TZ= or TZ= prefix from a cron spec,
+// reporting whether the spec had one.
+func splitCronTimezone(cronConfig string) (string, string, bool) {
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(cronConfig, prefix); ok {
+			timezone, spec, _ := strings.Cut(rest, " ")
+			return timezone, strings.TrimSpace(spec), true
+		}
+	}
+	return "", cronConfig, false
+}
+
+// getTimezone returns the time zone name set for a job, either by the
+// timezone key or a prefix on its cron spec.
+func getTimezone(config map[string]string) (string, bool) {
+	if timezone, ok := config[timezoneConfigKey]; ok {
+		return timezone, true
+	}
+
+	if config[configTypeKey] == configTypeCron {
+		timezone, _, ok := splitCronTimezone(config[cronConfigKey])
+		return timezone, ok
+	}
+	return "", false
+}
+
+// getLocation loads the IANA time zone set by the timezone key, or returns
+// nil if it is not set.
+func getLocation(config map[string]string) (*time.Location, error) {
+	timezone, ok := config[timezoneConfigKey]
+	if !ok {
+		return nil, nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" {
+		return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, timezoneConfigKey, timezone)
+	}
+	return location, nil
+}
+
+// getJobLocation returns the location a job must be scheduled in, or nil
+// for the provider default. gocron evaluates calendar jobs in the location
+// of their scheduler, while cron jobs carry their time zone in the spec.
+func getJobLocation(config map[string]string) (*time.Location, error) {
+	location, err := getLocation(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config[configTypeKey] {
+	case configTypeDaily, configTypeWeekly, configTypeMonthly:
+		return location, nil
+	default:
+		return nil, nil
+	}
+}
+
+// getEvery returns how many days, weeks or months a calendar job waits
+// between runs, which defaults to 1.
+func getEvery(config map[string]string) (uint, error) {
+	everyConfig, ok := config[everyConfigKey]
+	if !ok {
+		return 1, nil
+	}
+
+	every, err := strconv.ParseUint(everyConfig, 10, 32)
+	if err != nil || every == 0 {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, everyConfigKey, everyConfig)
+	}
+	return uint(every), nil
+}
+
+// getAtTimes parses a comma separated list of times of day, e.g.
+// "09:30,17:00" or "12:00:30".
+func getAtTimes(config map[string]string) (gocron.AtTimes, error) {
+	atConfig, ok := config[atConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, atConfigKey)
+	}
+
+	atTimes := []gocron.AtTime{}
+	for _, value := range strings.Split(atConfig, ",") {
+		value = strings.TrimSpace(value)
+
+		at, err := time.Parse("15:04:05", value)
+		if err != nil {
+			at, err = time.Parse("15:04", value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, atConfigKey, value)
+		}
+		atTimes = append(atTimes, gocron.NewAtTime(uint(at.Hour()), uint(at.Minute()), uint(at.Second())))
+	}
+
+	return gocron.NewAtTimes(atTimes[0], atTimes[1:]...), nil
+}
+
+// getWeekdays parses a comma separated list of weekday names, e.g.
+// "mon,wed" or "Monday,Wednesday".
+func getWeekdays(config map[string]string, key string) (gocron.Weekdays, error) {
+	daysConfig, ok := config[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, key)
+	}
+
+	weekdays := []time.Weekday{}
+	for _, value := range strings.Split(daysConfig, ",") {
+		value = strings.TrimSpace(value)
+
+		weekday, ok := weekdayNames[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, key, value)
+		}
+		weekdays = append(weekdays, weekday)
+	}
+
+	return gocron.NewWeekdays(weekdays[0], weekdays[1:]...), nil
+}
+
+// getDaysOfMonth parses a comma separated list of days of the month, where
+// negative days count back from the end of the month, e.g. "1,15,-1".
+func getDaysOfMonth(config map[string]string) (gocron.DaysOfTheMonth, error) {
+	daysConfig, ok := config[daysOfMonthConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, daysOfMonthConfigKey)
+	}
+
+	days := []int{}
+	for _, value := range strings.Split(daysConfig, ",") {
+		value = strings.TrimSpace(value)
+
+		day, err := strconv.Atoi(value)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, daysOfMonthConfigKey, value)
+		}
+		days = append(days, day)
+	}
+
+	return gocron.NewDaysOfTheMonth(days[0], days[1:]...), nil
+}
+
+// getMissedRuns returns the runs of an interval or cron job which were due
+// after the last scheduled run and before now, according to the link's
+// misfire policy.
+func getMissedRuns(config map[string]string, lastScheduled, now time.Time) ([]time.Time, error) {
+	limit, err := getMisfireLimit(config)
+	if err != nil || limit == 0 {
+		return nil, err
+	}
+
+	if lastScheduled.IsZero() {
+		return nil, nil
+	}
+
+	// The last scheduled time is restored with a fixed offset, which must be
+	// moved back to the job's time zone for runs after a DST change
+	location, err := getLocation(config)
+	if err != nil {
+		return nil, err
+	}
+	if location != nil {
+		lastScheduled = lastScheduled.In(location)
+		now = now.In(location)
+	}
+
+	next, err := newScheduleFunc(config, lastScheduled)
+	if err != nil || next == nil {
+		return nil, err
+	}
+
+	// Search back from now in growing windows, so a long outage of a frequent
+	// job does not step through every missed run
+	missed := []time.Time{}
+	for window := time.Minute; ; window *= 2 {
+		start := now.Add(-window)
+		if !start.After(lastScheduled) {
+			start = lastScheduled
+		}
+
+		missed = missed[:0]
+		for run := next(start); !run.IsZero() && run.Before(now); run = next(run) {
+			missed = append(missed, run)
+		}
+		if len(missed) >= limit || start.Equal(lastScheduled) {
+			break
+		}
+	}
+
+	// Keep the most recent runs up to the limit
+	if len(missed) > limit {
+		missed = missed[len(missed)-limit:]
+	}
+	return missed, nil
+}
+
+// getMisfireLimit returns the most catch-up runs fired for a link according
+// to its misfire policy, which is zero for the skip policy.
+func getMisfireLimit(config map[string]string) (int, error) {
+	policy, ok := config[misfireConfigKey]
+	if !ok {
+		policy = misfireConfigDefault
+	}
+
+	switch policy {
+	case misfireSkip:
+		return 0, nil
+	case misfireRunOnce:
+		return 1, nil
+	case misfireRunAll:
+		maxConfig, ok := config[misfireMaxConfigKey]
+		if !ok {
+			return misfireMaxDefault, nil
+		}
+
+		max, err := strconv.Atoi(maxConfig)
+		if err != nil {
+			return 0, fmt.Errorf("key %s: %w", misfireMaxConfigKey, err)
+		} else if max < 1 {
+			return 0, fmt.Errorf("key %s: must be at least 1", misfireMaxConfigKey)
+		}
+		return max, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidMisfire, policy)
+	}
+}
+
+// newScheduleFunc returns a function giving the run following a given time
+// for interval, cron and calendar jobs, or nil for other job types. Interval
+// runs are aligned to the anchor time, and calendar jobs count every n days,
+// weeks or months from it. Runs are moved to business days if the job has a
+// holiday calendar.
+func newScheduleFunc(config map[string]string, anchor time.Time) (func(time.Time) time.Time, error) {
+	next, err := newBaseScheduleFunc(config, anchor)
+	if err != nil || next == nil {
+		return next, err
+	}
+
+	calendar, err := getBusinessCalendar(config)
+	if err != nil {
+		return nil, err
+	} else if calendar != nil {
+		next = calendar.Adjust(next)
+	}
+	return next, nil
+}
+
+func newBaseScheduleFunc(config map[string]string, anchor time.Time) (func(time.Time) time.Time, error) {
+	switch config[configTypeKey] {
+	case configTypeInterval:
+		period, err := time.ParseDuration(config[intervalConfigKey])
+		if err != nil {
+			return nil, err
+		} else if period <= 0 {
+			return nil, nil
+		}
+		return func(t time.Time) time.Time {
+			if t.Before(anchor) {
+				return anchor
+			}
+			return anchor.Add((t.Sub(anchor)/period + 1) * period)
+		}, nil
+	case configTypeCron:
+		spec, err := getCronSpec(config)
+		if err != nil {
+			return nil, err
+		}
+
+		var schedule cron.Schedule
+		if config[cronSecConfigKey] == "true" {
+			parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+			schedule, err = parser.Parse(spec)
+		} else {
+			schedule, err = cron.ParseStandard(spec)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return schedule.Next, nil
+	case configTypeDaily, configTypeWeekly, configTypeMonthly:
+		return newCalendarScheduleFunc(config, anchor)
+	case configTypeRRule:
+		return newRRuleScheduleFunc(config, anchor)
+	case configTypeSolar:
+		schedule, err := getSolarSchedule(config)
+		if err != nil {
+			return nil, err
+		}
+		return schedule.Next, nil
+	default:
+		return nil, nil
+	}
+}
+
+// newCalendarScheduleFunc returns a function giving the run of a daily,
+// weekly or monthly job following a given time.
+func newCalendarScheduleFunc(config map[string]string, anchor time.Time) (func(time.Time) time.Time, error) {
+	every, err := getEvery(config)
+	if err != nil {
+		return nil, err
+	}
+
+	atTimes, err := getAtTimes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := getLocation(config)
+	if err != nil {
+		return nil, err
+	} else if location == nil {
+		location = time.Local
+	}
+
+	clock := []time.Time{}
+	for _, at := range atTimes() {
+		clock = append(clock, gocron.TimeFromAtTime(at, location))
+	}
+	slices.SortFunc(clock, time.Time.Compare)
+
+	anchor = anchor.In(location)
+	var onDay func(day time.Time) bool
+	switch config[configTypeKey] {
+	case configTypeDaily:
+		onDay = func(day time.Time) bool {
+			return isEvery(daysBetween(anchor, day), every)
+		}
+	case configTypeWeekly:
+		weekdays, err := getWeekdays(config, daysConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		onDay = func(day time.Time) bool {
+			// Weeks are counted from the Sunday starting the anchor's week
+			days := daysBetween(anchor, day) + int(anchor.Weekday())
+			weeks := days / 7
+			if days < 0 && days%7 != 0 {
+				weeks--
+			}
+			return slices.Contains(weekdays(), day.Weekday()) && isEvery(weeks, every)
+		}
+	case configTypeMonthly:
+		daysOfMonth, err := getDaysOfMonth(config)
+		if err != nil {
+			return nil, err
+		}
+		onDay = func(day time.Time) bool {
+			months := (day.Year()-anchor.Year())*12 + int(day.Month()) - int(anchor.Month())
+			if !isEvery(months, every) {
+				return false
+			}
+
+			lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, location).Day()
+			for _, dayOfMonth := range daysOfMonth() {
+				if dayOfMonth == day.Day() || dayOfMonth < 0 && lastDay+dayOfMonth+1 == day.Day() {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	maxDays := 366 * int(every)
+	return func(from time.Time) time.Time {
+		from = from.In(location)
+		start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+		for i := 0; i <= maxDays; i++ {
+			day := start.AddDate(0, 0, i)
+			if !onDay(day) {
+				continue
+			}
+
+			for _, at := range clock {
+				run := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), at.Second(), 0, location)
+				if run.After(from) {
+					return run
+				}
+			}
+		}
+		return time.Time{}
+	}, nil
+}
+
+// daysBetween returns the number of calendar days from one date to another.
+func daysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+// isEvery reports whether the nth day, week or month falls on an interval.
+func isEvery(n int, every uint) bool {
+	return (n%int(every)+int(every))%int(every) == 0
+}
+
+// isComputedSchedule reports whether the runs of a job are computed by the
+// provider rather than the scheduler.
+func isComputedSchedule(config map[string]string) bool {
+	_, ok := config[calendarConfigKey]
+	return ok || config[configTypeKey] == configTypeRRule || config[configTypeKey] == configTypeSolar
+}
+
+// getComputedRuns returns the runs of a schedule computed by the provider
+// after the given time, up to a year ahead.
+func getComputedRuns(next func(time.Time) time.Time, from time.Time) []time.Time {
+	runs := []time.Time{}
+	limit := from.Add(computedRunsHorizon)
+	for run := next(from); !run.IsZero() && run.Before(limit) && len(runs) < computedRunsMax; run = next(run) {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// getBusinessCalendar loads the holiday calendar of a cron or calendar job,
+// or returns nil if it does not have one.
+func getBusinessCalendar(config map[string]string) (*BusinessCalendar, error) {
+	calendarConfig, ok := config[calendarConfigKey]
+	if !ok {
+		return nil, nil
+	}
+
+	switch config[configTypeKey] {
+	case configTypeCron, configTypeDaily, configTypeWeekly, configTypeMonthly, configTypeRRule, configTypeSolar:
+	default:
+		return nil, fmt.Errorf("%w: key %s: not supported by type %s", ErrInvalidConfigValue, calendarConfigKey, config[configTypeKey])
+	}
+
+	holidays, err := loadHolidays(calendarConfig)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", calendarConfigKey, err)
+	}
+
+	businessDays, ok := config[businessDaysConfigKey]
+	if !ok {
+		businessDays = businessDaysDefault
+	}
+	workdays, err := getWeekdays(map[string]string{businessDaysConfigKey: businessDays}, businessDaysConfigKey)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, ok := config[calendarModeConfigKey]
+	if !ok {
+		mode = calendarModeDefault
+	}
+	switch mode {
+	case calendarModeSkip, calendarModeNext, calendarModePrevious:
+	default:
+		return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, calendarModeConfigKey, mode)
+	}
+
+	return NewBusinessCalendar(holidays, workdays(), mode), nil
+}
+
+func getTaskVersion(config map[string]string) (string, error) {
+	version, ok := config[versionConfigKey]
+	if !ok {
+		return versionConfigDefault, nil
+	}
+
+	switch version {
+	case tickerVersionLegacy, tickerVersionLatest:
+		return version, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidVersion, version)
+	}
+}
+
+func newRetryPolicy(config map[string]string) (*RetryPolicy, error) {
+	policy := &RetryPolicy{
+		Backoff:    retryBackoffDefault,
+		MaxBackoff: retryMaxBackoffDefault,
+	}
+
+	if maxConfig, ok := config[retryMaxConfigKey]; ok {
+		maxRetries, err := strconv.ParseUint(maxConfig, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", retryMaxConfigKey, err)
+		}
+		policy.MaxRetries = uint32(maxRetries)
+	}
+
+	if backoffConfig, ok := config[retryBackoffConfigKey]; ok {
+		backoff, err := time.ParseDuration(backoffConfig)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", retryBackoffConfigKey, err)
+		}
+		policy.Backoff = backoff
+	}
+
+	if maxBackoffConfig, ok := config[retryMaxBackoffConfigKey]; ok {
+		maxBackoff, err := time.ParseDuration(maxBackoffConfig)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", retryMaxBackoffConfigKey, err)
+		}
+		policy.MaxBackoff = maxBackoff
+	}
+
+	retryOnConfig, ok := config[retryOnConfigKey]
+	if !ok {
+		retryOnConfig = retryOnDefault
+	}
+	for _, retryOn := range strings.Split(retryOnConfig, ",") {
+		switch strings.TrimSpace(retryOn) {
+		case retryOnTransport:
+			policy.OnTransport = true
+		case retryOnTask:
+			policy.OnTask = true
+		case retryOnTimeout:
+			policy.OnTimeout = true
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRetryOn, retryOn)
+		}
+	}
+
+	return policy, nil
+}
+
+func getTimeout(config map[string]string, key string, defaultTimeout time.Duration) (time.Duration, error) {
+	timeoutConfig, ok := config[key]
+	if !ok {
+		return defaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(timeoutConfig)
+	if err != nil {
+		return 0, fmt.Errorf("key %s: %w", key, err)
+	}
+	return timeout, nil
+}
+
+func getDistributedMode(config map[string]string) (string, error) {
+	mode, ok := config[distributedConfigKey]
+	if !ok {
+		return distributedConfigDefault, nil
+	}
+
+	switch mode {
+	case distributedNone, distributedLock, distributedLeader:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidMode, mode)
+	}
+}
+
+func newLocker(config map[string]string, nc *nats.Conn) (*NatsLocker, error) {
+	bucket, ok := config[lockBucketConfigKey]
+	if !ok {
+		bucket = lockBucketDefault
+	}
+
+	ttl, err := getTimeout(config, lockTTLConfigKey, lockTTLDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	minHold, err := getTimeout(config, lockMinHoldConfigKey, lockMinHoldDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNatsLocker(context.Background(), nc, bucket, ttl, minHold)
+}
+
+func newElector(config map[string]string, nc *nats.Conn, id string, logger *slog.Logger) (*NatsElector, error) {
+	bucket, ok := config[leaderBucketConfigKey]
+	if !ok {
+		bucket = leaderBucketDefault
+	}
+
+	lease, err := getTimeout(config, leaderLeaseConfigKey, leaderLeaseDefault)
+	if err != nil {
+		return nil, err
+	}
+	if lease < time.Second {
+		return nil, fmt.Errorf("key %s: %w", leaderLeaseConfigKey, ErrInvalidLease)
+	}
+
+	return NewNatsElector(context.Background(), nc, bucket, id, lease, logger)
+}
+
+// getStateScope returns the lattice and provider which scope the state of a
+// provider instance, so instances do not share it.
+func getStateScope(lattice, providerID string) (string, string) {
+	if lattice == "" {
+		lattice = "default"
+	}
+	if providerID == "" {
+		providerID = "default"
+	}
+	return lattice, providerID
+}
+
+// getDefaultStateFile returns the state file used when `state_file` is not
+// set, in the user's config directory, or the temporary directory if there
+// is none, and scoped to the lattice and provider.
+func getDefaultStateFile(lattice, providerID string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	lattice, providerID = getStateScope(lattice, providerID)
+	return filepath.Join(dir, "ticker-provider", filepath.Base(lattice), filepath.Base(providerID), "state.json")
+}
+
+func newStateStore(config map[string]string, nc *nats.Conn, lattice, providerID string) (StateStore, error) {
+	store, ok := config[stateStoreConfigKey]
+	if !ok {
+		store = stateStoreConfigDefault
+	}
+
+	switch store {
+	case stateStoreNone:
+		return nil, nil
+	case stateStoreFile:
+		path, ok := config[stateFileConfigKey]
+		if !ok {
+			path = getDefaultStateFile(lattice, providerID)
+		}
+		fileStore, err := NewFileStore(path)
+		if err != nil {
+			return nil, err
+		}
+		return fileStore, nil
+	case stateStoreNats:
+		bucket, ok := config[stateBucketConfigKey]
+		if !ok {
+			bucket = stateBucketDefault
+		}
+		lattice, providerID := getStateScope(lattice, providerID)
+		natsStore, err := NewNatsStore(context.Background(), nc, bucket, lattice+"."+providerID)
+		if err != nil {
+			return nil, err
+		}
+		return natsStore, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStore, store)
+	}
+}
+
+func injectTraceHeader(_ctx context.Context) context.Context {
+	carrier := nats.Header{}
+	otel.GetTextMapPropagator().Inject(_ctx, NatsHeaderCarrier(carrier))
+	return wrpcnats.ContextWithHeader(_ctx, carrier)
+}
+
+func extractTraceHeader(_ctx context.Context) context.Context {
+	carrier, ok := wrpcnats.HeaderFromContext(_ctx)
+	if !ok {
+		return _ctx
+	}
+	return otel.GetTextMapPropagator().Extract(_ctx, NatsHeaderCarrier(carrier))
+}
+
+// newComputedJob schedules the runs of a recurrence rule or solar event up
+// to a year ahead, as gocron has no way to define a job's next run itself.
+func newComputedJob(config map[string]string) (gocron.JobDefinition, error) {
+	now := time.Now()
+	next, err := newScheduleFunc(config, now)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := getComputedRuns(next, now)
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("%w: type %s: %s: no runs left: %w", ErrInvalidConfigValue, config[configTypeKey], getScheduleDescription(config), ErrTimeInPast)
+	}
+	return gocron.OneTimeJob(gocron.OneTimeJobStartDateTimes(runs...)), nil
+}
+
+// newRRuleScheduleFunc returns a function giving the occurrence of a
+// recurrence rule following a given time. Rules without a DTSTART start from
+// the anchor time.
+func newRRuleScheduleFunc(config map[string]string, anchor time.Time) (func(time.Time) time.Time, error) {
+	set, err := getRRuleSet(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if set.GetDTStart().IsZero() {
+		location, err := getLocation(config)
+		if err != nil {
+			return nil, err
+		} else if location != nil {
+			anchor = anchor.In(location)
+		}
+		set.DTStart(anchor)
+	}
+
+	// Each iteration of a set starts from its DTSTART, so an iteration is
+	// kept and carried on for as long as runs are asked for in order.
+	// Iterating a set also sorts its dates in place
+	var (
+		mu      sync.Mutex
+		next    func() (time.Time, bool)
+		current time.Time
+	)
+	return func(from time.Time) time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if next == nil || from.Before(current) {
+			next, current = set.Iterator(), time.Time{}
+		}
+		for !current.After(from) {
+			run, ok := next()
+			if !ok {
+				return time.Time{}
+			}
+			current = run
+		}
+		return current
+	}, nil
+}
+
+// getRRuleSet parses the recurrence rule of a job, e.g.
+// "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1", along with any DTSTART and EXDATE
+// lines, and the dtstart and exdate keys. Times without a time zone are in
+// the job's timezone.
+func getRRuleSet(config map[string]string) (*rrule.Set, error) {
+	rruleConfig, ok := config[rruleConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, rruleConfigKey)
+	}
+
+	location, err := getLocation(config)
+	if err != nil {
+		return nil, err
+	} else if location == nil {
+		location = time.Local
+	}
+
+	// Lines without a property name are the rule itself
+	lines := []string{}
+	for _, line := range strings.Split(rruleConfig, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		end := strings.IndexAny(line, ";:")
+		if end < 0 || strings.Contains(line[:end], "=") {
+			line = "RRULE:" + line
+		}
+		lines = append(lines, line)
+	}
+
+	set, err := rrule.StrSliceToRRuleSetInLoc(lines, location)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %s: %s: %w", ErrInvalidConfigValue, rruleConfigKey, rruleConfig, err)
+	} else if set.GetRRule() == nil {
+		return nil, fmt.Errorf("%w: key %s: %s: no RRULE", ErrInvalidConfigValue, rruleConfigKey, rruleConfig)
+	} else if set.GetRRule().OrigOptions.Freq == rrule.SECONDLY {
+		return nil, fmt.Errorf("%w: key %s: %s: FREQ must be at least MINUTELY", ErrInvalidConfigValue, rruleConfigKey, rruleConfig)
+	}
+
+	if dtstartConfig, ok := config[dtstartConfigKey]; ok {
+		dtstart, err := time.Parse(time.RFC3339, dtstartConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, dtstartConfigKey, dtstartConfig)
+		}
+		set.DTStart(dtstart)
+	}
+
+	if exdateConfig, ok := config[exdateConfigKey]; ok {
+		for _, value := range strings.Split(exdateConfig, ",") {
+			value = strings.TrimSpace(value)
+
+			exdate, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, exdateConfigKey, value)
+			}
+			set.ExDate(exdate)
+		}
+	}
+	return set, nil
+}
+
+// getSolarSchedule parses the site, event and offset of a solar job.
+func getSolarSchedule(config map[string]string) (*SolarSchedule, error) {
+	latitude, err := getCoordinate(config, latitudeConfigKey, 90)
+	if err != nil {
+		return nil, err
+	}
+
+	longitude, err := getCoordinate(config, longitudeConfigKey, 180)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := config[eventConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: key %s", ErrMissingConfigValue, eventConfigKey)
+	}
+	switch event {
+	case solarEventSunrise, solarEventSunset, solarEventDawn, solarEventDusk, solarEventSolarNoon:
+	default:
+		return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, eventConfigKey, event)
+	}
+
+	var offset time.Duration
+	if offsetConfig, ok := config[offsetConfigKey]; ok {
+		offset, err = time.ParseDuration(offsetConfig)
+		if err != nil || offset.Abs() >= 24*time.Hour {
+			return nil, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, offsetConfigKey, offsetConfig)
+		}
+	}
+
+	return &SolarSchedule{
+		latitude:  latitude,
+		longitude: longitude,
+		event:     event,
+		offset:    offset,
+	}, nil
+}
+
+// getCoordinate parses a latitude or longitude in decimal degrees, which
+// must be within the given bound either side of zero.
+func getCoordinate(config map[string]string, key string, bound float64) (float64, error) {
+	value, ok := config[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: key %s", ErrMissingConfigValue, key)
+	}
+
+	coordinate, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(coordinate) || math.Abs(coordinate) > bound {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, key, value)
+	}
+	return coordinate, nil
+}
+
+// getFailureThreshold returns the number of consecutive failed runs after
+// which a task is unhealthy, where zero never marks a task unhealthy.
+func getFailureThreshold(config map[string]string) (uint64, error) {
+	thresholdConfig, ok := config[healthFailuresConfigKey]
+	if !ok {
+		return healthFailuresDefault, nil
+	}
+
+	threshold, err := strconv.ParseUint(thresholdConfig, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, healthFailuresConfigKey, thresholdConfig)
+	}
+	return threshold, nil
+}
+
+// getHistorySize returns the number of runs of a task kept in its history,
+// where zero disables the history.
+func getHistorySize(config map[string]string) (int, error) {
+	sizeConfig, ok := config[historySizeConfigKey]
+	if !ok {
+		return historySizeDefault, nil
+	}
+
+	size, err := strconv.Atoi(sizeConfig)
+	if err != nil || size < 0 || size > historySizeMax {
+		return 0, fmt.Errorf("%w: key %s: %s", ErrInvalidConfigValue, historySizeConfigKey, sizeConfig)
+	}
+	return size, nil
 }
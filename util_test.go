@@ -1,8 +1,13 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/go-co-op/gocron/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,3 +99,1434 @@ func TestNewSchedulerJob(t *testing.T) {
 		assert.NotNil(t, job)
 	})
 }
+
+func TestGetTaskVersion(t *testing.T) {
+
+	t.Run("default version", func(t *testing.T) {
+		version, err := getTaskVersion(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.1.0", version)
+	})
+
+	t.Run("latest version", func(t *testing.T) {
+		cfg := map[string]string{
+			"version": "0.2.0",
+		}
+
+		version, err := getTaskVersion(cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.2.0", version)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		cfg := map[string]string{
+			"version": "1.0.0",
+		}
+
+		_, err := getTaskVersion(cfg)
+		assert.ErrorIs(t, err, ErrInvalidVersion)
+	})
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+
+	t.Run("default policy", func(t *testing.T) {
+		policy, err := newRetryPolicy(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(0), policy.MaxRetries)
+		assert.Equal(t, time.Second, policy.Backoff)
+		assert.Equal(t, time.Minute, policy.MaxBackoff)
+		assert.True(t, policy.OnTransport)
+		assert.True(t, policy.OnTask)
+		assert.True(t, policy.OnTimeout)
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		cfg := map[string]string{
+			"retry_max":         "3",
+			"retry_backoff":     "5s",
+			"retry_max_backoff": "30s",
+			"retry_on":          "task",
+		}
+
+		policy, err := newRetryPolicy(cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(3), policy.MaxRetries)
+		assert.Equal(t, 5*time.Second, policy.Backoff)
+		assert.Equal(t, 30*time.Second, policy.MaxBackoff)
+		assert.False(t, policy.OnTransport)
+		assert.True(t, policy.OnTask)
+	})
+
+	t.Run("invalid retry_max", func(t *testing.T) {
+		cfg := map[string]string{
+			"retry_max": "-1",
+		}
+
+		_, err := newRetryPolicy(cfg)
+		assert.ErrorContains(t, err, "retry_max")
+	})
+
+	t.Run("invalid retry_backoff", func(t *testing.T) {
+		cfg := map[string]string{
+			"retry_backoff": "abcd",
+		}
+
+		_, err := newRetryPolicy(cfg)
+		assert.ErrorContains(t, err, "retry_backoff")
+	})
+
+	t.Run("invalid retry_on", func(t *testing.T) {
+		cfg := map[string]string{
+			"retry_on": "transport,abcd",
+		}
+
+		_, err := newRetryPolicy(cfg)
+		assert.ErrorIs(t, err, ErrInvalidRetryOn)
+	})
+}
+
+func TestGetTimeout(t *testing.T) {
+
+	t.Run("default timeout", func(t *testing.T) {
+		timeout, err := getTimeout(map[string]string{}, "timeout", 30*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, timeout)
+	})
+
+	t.Run("valid timeout", func(t *testing.T) {
+		cfg := map[string]string{
+			"timeout": "5s",
+		}
+
+		timeout, err := getTimeout(cfg, "timeout", 30*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Second, timeout)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		cfg := map[string]string{
+			"timeout": "abcd",
+		}
+
+		_, err := getTimeout(cfg, "timeout", 30*time.Second)
+		assert.ErrorContains(t, err, "timeout")
+	})
+}
+
+func TestNewSchedulerOptions(t *testing.T) {
+
+	t.Run("default overlap", func(t *testing.T) {
+		options, err := newSchedulerOptions(map[string]string{})
+		assert.NoError(t, err)
+		assert.Empty(t, options)
+	})
+
+	t.Run("skip overlap", func(t *testing.T) {
+		cfg := map[string]string{
+			"overlap": "skip",
+		}
+
+		options, err := newSchedulerOptions(cfg)
+		assert.NoError(t, err)
+		assert.Len(t, options, 1)
+	})
+
+	t.Run("queue overlap", func(t *testing.T) {
+		cfg := map[string]string{
+			"overlap": "queue",
+		}
+
+		options, err := newSchedulerOptions(cfg)
+		assert.NoError(t, err)
+		assert.Len(t, options, 1)
+	})
+
+	t.Run("invalid overlap", func(t *testing.T) {
+		cfg := map[string]string{
+			"overlap": "abcd",
+		}
+
+		_, err := newSchedulerOptions(cfg)
+		assert.ErrorIs(t, err, ErrInvalidOverlap)
+	})
+}
+
+func TestGetMissedRuns(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 30, 0, time.UTC)
+	minutes := func(m ...int) []time.Time {
+		runs := []time.Time{}
+		for _, minute := range m {
+			runs = append(runs, time.Date(2024, 6, 1, 11, minute, 0, 0, time.UTC))
+		}
+		return runs
+	}
+	seconds := func(s ...int) []time.Time {
+		runs := []time.Time{}
+		for _, second := range s {
+			runs = append(runs, time.Date(2024, 6, 1, 12, 0, second, 0, time.UTC))
+		}
+		return runs
+	}
+
+	tests := []struct {
+		name          string
+		config        map[string]string
+		lastScheduled time.Time
+		expected      []time.Time
+		err           error
+	}{
+		{
+			name: "default skip",
+			config: map[string]string{
+				"type":   "interval",
+				"period": "10m",
+			},
+			lastScheduled: now.Add(-time.Hour),
+		},
+		{
+			name: "never run",
+			config: map[string]string{
+				"type":    "interval",
+				"period":  "10m",
+				"misfire": "run-all",
+			},
+		},
+		{
+			name: "interval run once",
+			config: map[string]string{
+				"type":    "interval",
+				"period":  "10m",
+				"misfire": "run-once",
+			},
+			lastScheduled: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+			expected:      minutes(60),
+		},
+		{
+			name: "interval run all",
+			config: map[string]string{
+				"type":    "interval",
+				"period":  "10m",
+				"misfire": "run-all",
+			},
+			lastScheduled: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+			expected:      minutes(10, 20, 30, 40, 50, 60),
+		},
+		{
+			name: "interval run all capped",
+			config: map[string]string{
+				"type":        "interval",
+				"period":      "10m",
+				"misfire":     "run-all",
+				"misfire_max": "2",
+			},
+			lastScheduled: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+			expected:      minutes(50, 60),
+		},
+		{
+			name: "interval nothing missed",
+			config: map[string]string{
+				"type":    "interval",
+				"period":  "10m",
+				"misfire": "run-all",
+			},
+			lastScheduled: now.Add(-5 * time.Minute),
+			expected:      []time.Time{},
+		},
+		{
+			name: "cron run all",
+			config: map[string]string{
+				"type":    "cron",
+				"cron":    "*/15 * * * *",
+				"misfire": "run-all",
+			},
+			lastScheduled: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+			expected:      minutes(15, 30, 45, 60),
+		},
+		{
+			name: "cron seconds run once",
+			config: map[string]string{
+				"type":    "cron",
+				"cron":    "0 */15 * * * *",
+				"seconds": "true",
+				"misfire": "run-once",
+			},
+			lastScheduled: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+			expected:      minutes(60),
+		},
+		{
+			name: "long outage capped",
+			config: map[string]string{
+				"type":    "cron",
+				"cron":    "* * * * * *",
+				"seconds": "true",
+				"misfire": "run-all",
+			},
+			lastScheduled: now.AddDate(-1, 0, 0),
+			expected:      seconds(20, 21, 22, 23, 24, 25, 26, 27, 28, 29),
+		},
+		{
+			name: "interval long outage capped",
+			config: map[string]string{
+				"type":    "interval",
+				"period":  "1s",
+				"misfire": "run-all",
+			},
+			lastScheduled: now.AddDate(-1, 0, 0),
+			expected:      seconds(20, 21, 22, 23, 24, 25, 26, 27, 28, 29),
+		},
+		{
+			name: "invalid policy",
+			config: map[string]string{
+				"type":    "interval",
+				"period":  "10m",
+				"misfire": "abcd",
+			},
+			err: ErrInvalidMisfire,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			missed, err := getMissedRuns(test.config, test.lastScheduled, now)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, missed)
+		})
+	}
+
+	t.Run("restored over a dst change", func(t *testing.T) {
+		london, err := time.LoadLocation("Europe/London")
+		assert.NoError(t, err)
+
+		// State is restored from JSON with the summer time offset of the
+		// last run, after the clocks have gone back
+		data, err := json.Marshal(time.Date(2026, 10, 24, 9, 0, 0, 0, london))
+		assert.NoError(t, err)
+		lastScheduled := time.Time{}
+		assert.NoError(t, json.Unmarshal(data, &lastScheduled))
+
+		missed, err := getMissedRuns(map[string]string{
+			"type":     "daily",
+			"at":       "09:00",
+			"timezone": "Europe/London",
+			"misfire":  "run-all",
+		}, lastScheduled, time.Date(2026, 10, 26, 10, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		if assert.Len(t, missed, 2) {
+			assert.True(t, time.Date(2026, 10, 25, 9, 0, 0, 0, time.UTC).Equal(missed[0]))
+			assert.True(t, time.Date(2026, 10, 26, 9, 0, 0, 0, time.UTC).Equal(missed[1]))
+		}
+	})
+
+	t.Run("invalid max", func(t *testing.T) {
+		_, err := getMissedRuns(map[string]string{
+			"type":        "interval",
+			"period":      "10m",
+			"misfire":     "run-all",
+			"misfire_max": "0",
+		}, now.Add(-time.Hour), now)
+		assert.ErrorContains(t, err, "misfire_max")
+	})
+}
+
+func TestGetChangedKeys(t *testing.T) {
+	previous := map[string]string{
+		"type":    "interval",
+		"period":  "10s",
+		"timeout": "5s",
+	}
+
+	assert.Empty(t, getChangedKeys(previous, map[string]string{
+		"type":    "interval",
+		"period":  "10s",
+		"timeout": "5s",
+	}))
+	assert.Equal(t, []string{"overlap", "period", "timeout"}, getChangedKeys(previous, map[string]string{
+		"type":    "interval",
+		"period":  "1m",
+		"overlap": "skip",
+	}))
+}
+
+func TestGetLinkSchedules(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected map[string]map[string]string
+		err      error
+	}{
+		{
+			name: "single schedule",
+			config: map[string]string{
+				"type":   "interval",
+				"period": "10s",
+			},
+			expected: map[string]map[string]string{
+				"": {
+					"type":   "interval",
+					"period": "10s",
+				},
+			},
+		},
+		{
+			name: "prefixed keys",
+			config: map[string]string{
+				"timeout":                "5s",
+				"schedule.daytime.type":  "cron",
+				"schedule.daytime.cron":  "*/5 8-18 * * *",
+				"schedule.midnight.type": "cron",
+				"schedule.midnight.cron": "0 0 * * *",
+			},
+			expected: map[string]map[string]string{
+				"daytime": {
+					"timeout": "5s",
+					"type":    "cron",
+					"cron":    "*/5 8-18 * * *",
+				},
+				"midnight": {
+					"timeout": "5s",
+					"type":    "cron",
+					"cron":    "0 0 * * *",
+				},
+			},
+		},
+		{
+			name: "json schedules",
+			config: map[string]string{
+				"retry_max": "3",
+				"schedules": `{"daytime": {"type": "interval", "period": "5m"}, "midnight": {"type": "cron", "cron": "0 0 * * *", "retry_max": 5}}`,
+			},
+			expected: map[string]map[string]string{
+				"daytime": {
+					"retry_max": "3",
+					"type":      "interval",
+					"period":    "5m",
+				},
+				"midnight": {
+					"retry_max": "5",
+					"type":      "cron",
+					"cron":      "0 0 * * *",
+				},
+			},
+		},
+		{
+			name: "duplicate schedule",
+			config: map[string]string{
+				"schedule.daytime.period": "5m",
+				"schedules":               `{"daytime": {"period": "10m"}}`,
+			},
+			err: ErrInvalidSchedule,
+		},
+		{
+			name: "invalid prefixed key",
+			config: map[string]string{
+				"schedule.daytime": "5m",
+			},
+			err: ErrInvalidSchedule,
+		},
+		{
+			name: "invalid name",
+			config: map[string]string{
+				"schedules": `{"day time": {"period": "10m"}}`,
+			},
+			err: ErrInvalidSchedule,
+		},
+		{
+			name: "invalid json",
+			config: map[string]string{
+				"schedules": `{"daytime": "10m"}`,
+			},
+			err: ErrInvalidSchedule,
+		},
+		{
+			name: "nested json value",
+			config: map[string]string{
+				"schedules": `{"daytime": {"period": {"every": "10m"}}}`,
+			},
+			err: ErrInvalidConfigValue,
+		},
+		{
+			name: "array json value",
+			config: map[string]string{
+				"schedules": `{"daytime": {"times": ["10:00", "12:00"]}}`,
+			},
+			err: ErrInvalidConfigValue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedules, err := getLinkSchedules(test.config)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, schedules)
+		})
+	}
+}
+
+func TestNewCalendarJob(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		err    string
+	}{
+		{
+			name: "daily",
+			config: map[string]string{
+				"type": "daily",
+				"at":   "09:30, 17:00:15",
+			},
+		},
+		{
+			name: "daily every other day",
+			config: map[string]string{
+				"type":  "daily",
+				"at":    "09:30",
+				"every": "2",
+			},
+		},
+		{
+			name: "daily missing at",
+			config: map[string]string{
+				"type": "daily",
+			},
+			err: "key at",
+		},
+		{
+			name: "daily invalid at",
+			config: map[string]string{
+				"type": "daily",
+				"at":   "09:30,25:00",
+			},
+			err: "key at: 25:00",
+		},
+		{
+			name: "daily invalid every",
+			config: map[string]string{
+				"type":  "daily",
+				"at":    "09:30",
+				"every": "0",
+			},
+			err: "key every",
+		},
+		{
+			name: "weekly",
+			config: map[string]string{
+				"type": "weekly",
+				"days": "Mon,wednesday",
+				"at":   "09:30,17:00",
+			},
+		},
+		{
+			name: "weekly missing days",
+			config: map[string]string{
+				"type": "weekly",
+				"at":   "09:30",
+			},
+			err: "key days",
+		},
+		{
+			name: "weekly invalid days",
+			config: map[string]string{
+				"type": "weekly",
+				"days": "mon,funday",
+				"at":   "09:30",
+			},
+			err: "key days: funday",
+		},
+		{
+			name: "monthly",
+			config: map[string]string{
+				"type":          "monthly",
+				"days_of_month": "1,15,-1",
+				"at":            "00:00",
+			},
+		},
+		{
+			name: "monthly missing days",
+			config: map[string]string{
+				"type": "monthly",
+				"at":   "00:00",
+			},
+			err: "key days_of_month",
+		},
+		{
+			name: "monthly invalid days",
+			config: map[string]string{
+				"type":          "monthly",
+				"days_of_month": "1,0",
+				"at":            "00:00",
+			},
+			err: "key days_of_month: 0",
+		},
+		{
+			name: "monthly out of range days",
+			config: map[string]string{
+				"type":          "monthly",
+				"days_of_month": "-32",
+				"at":            "00:00",
+			},
+			err: "key days_of_month: -32",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job, err := newSchedulerJob(test.config)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, job)
+		})
+	}
+
+	t.Run("weekly next run", func(t *testing.T) {
+		s, err := gocron.NewScheduler()
+		assert.NoError(t, err)
+		defer s.Shutdown()
+
+		job, err := newSchedulerJob(map[string]string{
+			"type": "weekly",
+			"days": "mon,wed",
+			"at":   "09:30,17:00",
+		})
+		assert.NoError(t, err)
+
+		j, err := s.NewJob(job, gocron.NewTask(func() {}))
+		assert.NoError(t, err)
+		s.Start()
+
+		nextRuns, err := j.NextRuns(4)
+		assert.NoError(t, err)
+		for _, nextRun := range nextRuns {
+			assert.Contains(t, []time.Weekday{time.Monday, time.Wednesday}, nextRun.Weekday())
+			assert.Contains(t, []string{"09:30:00", "17:00:00"}, nextRun.Format(time.TimeOnly))
+		}
+	})
+
+	t.Run("monthly last day", func(t *testing.T) {
+		s, err := gocron.NewScheduler()
+		assert.NoError(t, err)
+		defer s.Shutdown()
+
+		job, err := newSchedulerJob(map[string]string{
+			"type":          "monthly",
+			"days_of_month": "-1",
+			"at":            "12:00",
+		})
+		assert.NoError(t, err)
+
+		j, err := s.NewJob(job, gocron.NewTask(func() {}))
+		assert.NoError(t, err)
+		s.Start()
+
+		nextRuns, err := j.NextRuns(3)
+		assert.NoError(t, err)
+		for _, nextRun := range nextRuns {
+			assert.Equal(t, 1, nextRun.AddDate(0, 0, 1).Day())
+		}
+	})
+}
+
+func TestGetCronSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected string
+		err      string
+	}{
+		{
+			name: "no time zone",
+			config: map[string]string{
+				"cron": "0 9 * * *",
+			},
+			expected: "0 9 * * *",
+		},
+		{
+			name: "timezone key",
+			config: map[string]string{
+				"cron":     "0 9 * * *",
+				"timezone": "Europe/London",
+			},
+			expected: "CRON_TZ=Europe/London 0 9 * * *",
+		},
+		{
+			name: "cron prefix",
+			config: map[string]string{
+				"cron": "CRON_TZ=America/New_York 0 9 * * *",
+			},
+			expected: "CRON_TZ=America/New_York 0 9 * * *",
+		},
+		{
+			name: "tz prefix",
+			config: map[string]string{
+				"cron": "TZ=Asia/Tokyo 0 9 * * *",
+			},
+			expected: "CRON_TZ=Asia/Tokyo 0 9 * * *",
+		},
+		{
+			name: "matching prefix and key",
+			config: map[string]string{
+				"cron":     "CRON_TZ=Asia/Tokyo 0 9 * * *",
+				"timezone": "Asia/Tokyo",
+			},
+			expected: "CRON_TZ=Asia/Tokyo 0 9 * * *",
+		},
+		{
+			name: "conflicting prefix and key",
+			config: map[string]string{
+				"cron":     "CRON_TZ=Asia/Tokyo 0 9 * * *",
+				"timezone": "Europe/London",
+			},
+			err: "key timezone: Europe/London does not match cron time zone Asia/Tokyo",
+		},
+		{
+			name: "invalid timezone key",
+			config: map[string]string{
+				"cron":     "0 9 * * *",
+				"timezone": "Mars/Olympus_Mons",
+			},
+			err: "key timezone: Mars/Olympus_Mons",
+		},
+		{
+			name: "invalid cron prefix",
+			config: map[string]string{
+				"cron": "CRON_TZ=Mars/Olympus_Mons 0 9 * * *",
+			},
+			err: "key cron: Mars/Olympus_Mons",
+		},
+		{
+			name:   "missing cron",
+			config: map[string]string{},
+			err:    "key cron",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := getCronSpec(test.config)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, spec)
+		})
+	}
+
+	t.Run("missed runs in time zone", func(t *testing.T) {
+		missed, err := getMissedRuns(map[string]string{
+			"type":     "cron",
+			"cron":     "0 9 * * *",
+			"timezone": "America/New_York",
+			"misfire":  "run-all",
+		}, time.Date(2024, 5, 30, 13, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Len(t, missed, 1)
+		assert.True(t, missed[0].Equal(time.Date(2024, 5, 31, 13, 0, 0, 0, time.UTC)))
+	})
+}
+
+func TestGetJobLocation(t *testing.T) {
+	location, err := getJobLocation(map[string]string{
+		"type":     "daily",
+		"at":       "09:00",
+		"timezone": "Europe/Paris",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", location.String())
+
+	location, err = getJobLocation(map[string]string{
+		"type":     "cron",
+		"cron":     "0 9 * * *",
+		"timezone": "Europe/Paris",
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, location)
+
+	_, err = getJobLocation(map[string]string{
+		"type":     "interval",
+		"period":   "1h",
+		"timezone": "Nowhere",
+	})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+}
+
+func TestNewRandomJob(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		err    string
+	}{
+		{
+			name: "valid",
+			config: map[string]string{
+				"type":       "random",
+				"min_period": "50s",
+				"max_period": "70s",
+			},
+		},
+		{
+			name: "fixed period",
+			config: map[string]string{
+				"type":       "random",
+				"min_period": "1m",
+				"max_period": "1m",
+			},
+		},
+		{
+			name: "missing min period",
+			config: map[string]string{
+				"type":       "random",
+				"max_period": "70s",
+			},
+			err: "key min_period",
+		},
+		{
+			name: "invalid max period",
+			config: map[string]string{
+				"type":       "random",
+				"min_period": "50s",
+				"max_period": "abcd",
+			},
+			err: "key max_period: abcd",
+		},
+		{
+			name: "negative min period",
+			config: map[string]string{
+				"type":       "random",
+				"min_period": "-1s",
+				"max_period": "70s",
+			},
+			err: "key min_period: -1s",
+		},
+		{
+			name: "max less than min",
+			config: map[string]string{
+				"type":       "random",
+				"min_period": "70s",
+				"max_period": "50s",
+			},
+			err: "key max_period: 50s is less than min_period",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job, err := newSchedulerJob(test.config)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, job)
+		})
+	}
+}
+
+func TestGetJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected time.Duration
+		err      string
+	}{
+		{
+			name: "default",
+			config: map[string]string{
+				"type":   "interval",
+				"period": "1m",
+			},
+		},
+		{
+			name: "interval",
+			config: map[string]string{
+				"type":   "interval",
+				"period": "1m",
+				"jitter": "10s",
+			},
+			expected: 10 * time.Second,
+		},
+		{
+			name: "cron",
+			config: map[string]string{
+				"type":   "cron",
+				"cron":   "* * * * *",
+				"jitter": "30s",
+			},
+			expected: 30 * time.Second,
+		},
+		{
+			name: "not less than period",
+			config: map[string]string{
+				"type":   "interval",
+				"period": "1m",
+				"jitter": "1m",
+			},
+			err: "key jitter: 1m must be less than period",
+		},
+		{
+			name: "invalid",
+			config: map[string]string{
+				"type":   "interval",
+				"period": "1m",
+				"jitter": "-1s",
+			},
+			err: "key jitter: -1s",
+		},
+		{
+			name: "unsupported type",
+			config: map[string]string{
+				"type":   "startup",
+				"delay":  "1s",
+				"jitter": "1s",
+			},
+			err: "key jitter: not supported by type startup",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jitter, err := getJitter(test.config)
+			if test.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidConfigValue)
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, jitter)
+		})
+	}
+}
+
+func TestGetOnceTimes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	future, past, err := getOnceTimes(map[string]string{
+		"times": "2026-11-01T03:00:00Z, 2025-06-01T12:00:00+01:00,2026-02-01T00:00:00Z",
+	}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 11, 1, 3, 0, 0, 0, time.UTC),
+	}, future)
+	assert.Len(t, past, 1)
+	assert.True(t, past[0].Equal(time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)))
+
+	_, _, err = getOnceTimes(map[string]string{
+		"times": "2026-11-01 03:00",
+	}, now)
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+	assert.ErrorContains(t, err, "key times: 2026-11-01 03:00")
+
+	_, _, err = getOnceTimes(map[string]string{}, now)
+	assert.ErrorIs(t, err, ErrMissingConfigValue)
+
+	_, err = newOnceJob(map[string]string{
+		"times": "2099-01-01T00:00:00Z,2000-01-01T00:00:00Z",
+	})
+	assert.ErrorIs(t, err, ErrTimeInPast)
+	assert.ErrorContains(t, err, "2000-01-01T00:00:00Z")
+}
+
+func TestGetActiveWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		startAt time.Time
+		endAt   time.Time
+		err     string
+	}{
+		{
+			name:   "not set",
+			config: map[string]string{},
+		},
+		{
+			name: "start and end",
+			config: map[string]string{
+				"start_at": "2026-11-01T00:00:00Z",
+				"end_at":   "2026-12-01T00:00:00Z",
+			},
+			startAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			endAt:   time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "end only",
+			config: map[string]string{
+				"end_at": "2026-12-01T00:00:00Z",
+			},
+			endAt: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "invalid start",
+			config: map[string]string{
+				"start_at": "tomorrow",
+			},
+			err: "key start_at: tomorrow",
+		},
+		{
+			name: "end before start",
+			config: map[string]string{
+				"start_at": "2026-12-01T00:00:00Z",
+				"end_at":   "2026-11-01T00:00:00Z",
+			},
+			err: "key end_at: 2026-11-01T00:00:00Z is not after start_at",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			startAt, endAt, err := getActiveWindow(test.config)
+			if test.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidConfigValue)
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.startAt.Equal(startAt))
+			assert.True(t, test.endAt.Equal(endAt))
+		})
+	}
+}
+
+func TestGetMaxRuns(t *testing.T) {
+	maxRuns, err := getMaxRuns(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), maxRuns)
+
+	maxRuns, err = getMaxRuns(map[string]string{"max_runs": "5"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), maxRuns)
+
+	_, err = getMaxRuns(map[string]string{"max_runs": "0"})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+
+	_, err = getMaxRuns(map[string]string{"max_runs": "-1"})
+	assert.ErrorContains(t, err, "key max_runs: -1")
+}
+
+func TestGetDefaultStateFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, err := os.UserConfigDir()
+	assert.NoError(t, err)
+
+	path := getDefaultStateFile("default", "VPROVIDER")
+	assert.Equal(t, filepath.Join(dir, "ticker-provider", "default", "VPROVIDER", "state.json"), path)
+
+	// Instances of the provider on other lattices keep their own state
+	other := getDefaultStateFile("staging", "VPROVIDER")
+	assert.NotEqual(t, path, other)
+
+	path = getDefaultStateFile("", "")
+	assert.Equal(t, filepath.Join(dir, "ticker-provider", "default", "default", "state.json"), path)
+
+	// Hosts without a config directory keep state in the temporary directory
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "")
+	path = getDefaultStateFile("default", "VPROVIDER")
+	assert.Equal(t, filepath.Join(os.TempDir(), "ticker-provider", "default", "VPROVIDER", "state.json"), path)
+}
+
+func TestGetHistorySize(t *testing.T) {
+	size, err := getHistorySize(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, historySizeDefault, size)
+
+	size, err = getHistorySize(map[string]string{"history_size": "0"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+
+	size, err = getHistorySize(map[string]string{"history_size": "100"})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, size)
+
+	_, err = getHistorySize(map[string]string{"history_size": "-1"})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+
+	_, err = getHistorySize(map[string]string{"history_size": "many"})
+	assert.ErrorContains(t, err, "key history_size: many")
+}
+
+func TestGetBlackouts(t *testing.T) {
+	windows, err := getBlackouts(map[string]string{})
+	assert.NoError(t, err)
+	assert.Empty(t, windows)
+
+	windows, err = getBlackouts(map[string]string{
+		"blackout": "0 9 * * * for 1h",
+		"timezone": "Asia/Tokyo",
+	})
+	assert.NoError(t, err)
+	_, ok := getBlackout(windows, time.Date(2026, 7, 1, 0, 30, 0, 0, time.UTC))
+	assert.True(t, ok)
+
+	_, err = getBlackouts(map[string]string{
+		"blackout": "0 9 * * * for 1h",
+		"timezone": "Nowhere",
+	})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+
+	_, err = getBlackouts(map[string]string{
+		"blackout": "0 9 * * *",
+	})
+	assert.ErrorIs(t, err, ErrInvalidBlackout)
+	assert.ErrorContains(t, err, "key blackout")
+}
+
+func TestNewCalendarScheduleFunc(t *testing.T) {
+	// Wednesday 1st July 2026
+	anchor := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected []time.Time
+	}{
+		{
+			name: "daily every two days",
+			config: map[string]string{
+				"type":  "daily",
+				"at":    "09:00,18:00",
+				"every": "2",
+			},
+			expected: []time.Time{
+				time.Date(2026, 7, 1, 18, 0, 0, 0, time.UTC),
+				time.Date(2026, 7, 3, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 7, 3, 18, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "weekly every two weeks",
+			config: map[string]string{
+				"type":  "weekly",
+				"days":  "mon,thu",
+				"at":    "09:00",
+				"every": "2",
+			},
+			expected: []time.Time{
+				time.Date(2026, 7, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 7, 13, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 7, 16, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 7, 27, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "monthly last day",
+			config: map[string]string{
+				"type":          "monthly",
+				"days_of_month": "-1",
+				"at":            "09:00",
+			},
+			expected: []time.Time{
+				time.Date(2026, 7, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 8, 31, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config["timezone"] = "UTC"
+			next, err := newScheduleFunc(test.config, anchor)
+			assert.NoError(t, err)
+
+			run := anchor
+			for _, expected := range test.expected {
+				run = next(run)
+				assert.Equal(t, expected, run)
+			}
+		})
+	}
+}
+
+func TestGetBusinessCalendar(t *testing.T) {
+	calendar, err := getBusinessCalendar(map[string]string{"type": "cron"})
+	assert.NoError(t, err)
+	assert.Nil(t, calendar)
+
+	calendar, err = getBusinessCalendar(map[string]string{
+		"type":          "cron",
+		"calendar":      "2026-12-25",
+		"business_days": "mon,tue,wed,thu,fri,sat",
+	})
+	assert.NoError(t, err)
+	assert.False(t, calendar.IsBusinessDay(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, calendar.IsBusinessDay(time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, calendar.IsBusinessDay(time.Date(2026, 12, 27, 0, 0, 0, 0, time.UTC)))
+
+	next, err := newScheduleFunc(map[string]string{
+		"type":          "cron",
+		"cron":          "CRON_TZ=UTC 0 9 * * *",
+		"calendar":      "2026-12-25",
+		"calendar_mode": "previous-business-day",
+	}, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 28, 9, 0, 0, 0, time.UTC), next(time.Date(2026, 12, 24, 9, 0, 0, 0, time.UTC)))
+
+	_, err = getBusinessCalendar(map[string]string{
+		"type":     "interval",
+		"calendar": "2026-12-25",
+	})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+	assert.ErrorContains(t, err, "not supported by type interval")
+
+	_, err = getBusinessCalendar(map[string]string{
+		"type":          "cron",
+		"calendar":      "2026-12-25",
+		"calendar_mode": "nearest-business-day",
+	})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+
+	_, err = getBusinessCalendar(map[string]string{
+		"type":          "cron",
+		"calendar":      "2026-12-25",
+		"business_days": "mon,someday",
+	})
+	assert.ErrorIs(t, err, ErrInvalidConfigValue)
+
+	_, err = getBusinessCalendar(map[string]string{
+		"type":     "cron",
+		"calendar": "25/12/2026",
+	})
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+	assert.ErrorContains(t, err, "key calendar")
+}
+
+func TestNewRRuleScheduleFunc(t *testing.T) {
+	anchor := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected []time.Time
+		err      string
+	}{
+		{
+			name: "first monday of the month",
+			config: map[string]string{
+				"rrule":   "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1",
+				"dtstart": "2026-01-01T09:00:00Z",
+			},
+			expected: []time.Time{
+				time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 6, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "last friday of the month",
+			config: map[string]string{
+				"rrule":   "RRULE:FREQ=MONTHLY;BYDAY=FR;BYSETPOS=-1",
+				"dtstart": "2026-01-01T17:00:00Z",
+			},
+			expected: []time.Time{
+				time.Date(2026, 1, 30, 17, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 27, 17, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 27, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "inline start and excluded dates",
+			config: map[string]string{
+				"rrule": "DTSTART:20260101T090000Z\nRRULE:FREQ=DAILY;COUNT=5\nEXDATE:20260103T090000Z",
+			},
+			expected: []time.Time{
+				time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name: "excluded dates key",
+			config: map[string]string{
+				"rrule":   "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+				"dtstart": "2026-01-05T09:00:00Z",
+				"exdate":  "2026-01-07T09:00:00Z, 2026-01-12T09:00:00Z",
+			},
+			expected: []time.Time{
+				time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 9, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 14, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "local start across daylight saving",
+			config: map[string]string{
+				"rrule":    "DTSTART:20260305T090000\nRRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=3",
+				"timezone": "America/New_York",
+			},
+			expected: []time.Time{
+				time.Date(2026, 3, 5, 14, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 12, 13, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name: "start from anchor",
+			config: map[string]string{
+				"rrule": "FREQ=HOURLY;INTERVAL=6",
+			},
+			expected: []time.Time{
+				time.Date(2026, 1, 1, 6, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "invalid rule",
+			config: map[string]string{
+				"rrule": "FREQ=FORTNIGHTLY",
+			},
+			err: "key rrule",
+		},
+		{
+			name: "no rule",
+			config: map[string]string{
+				"rrule": "DTSTART:20260101T090000Z",
+			},
+			err: "no RRULE",
+		},
+		{
+			name: "sub-minute rule",
+			config: map[string]string{
+				"rrule": "FREQ=SECONDLY;INTERVAL=30",
+			},
+			err: "FREQ must be at least MINUTELY",
+		},
+		{
+			name: "invalid start",
+			config: map[string]string{
+				"rrule":   "FREQ=DAILY",
+				"dtstart": "2026-01-01",
+			},
+			err: "key dtstart",
+		},
+		{
+			name: "invalid excluded date",
+			config: map[string]string{
+				"rrule":  "FREQ=DAILY",
+				"exdate": "2026-01-01",
+			},
+			err: "key exdate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config["type"] = "rrule"
+			next, err := newScheduleFunc(test.config, anchor)
+			if test.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidConfigValue)
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			run := anchor
+			for _, expected := range test.expected {
+				run = next(run)
+				assert.True(t, expected.Equal(run), "expected %s, got %s", expected, run)
+			}
+
+			// Runs asked for out of order start a new iteration
+			assert.True(t, test.expected[0].Equal(next(anchor)))
+		})
+	}
+}
+
+func TestNewRRuleJob(t *testing.T) {
+	job, err := newSchedulerJob(map[string]string{
+		"type":  "rrule",
+		"rrule": "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, job)
+
+	_, err = newSchedulerJob(map[string]string{
+		"type":  "rrule",
+		"rrule": "DTSTART:20200101T090000Z\nRRULE:FREQ=DAILY;UNTIL=20200201T000000Z",
+	})
+	assert.ErrorIs(t, err, ErrTimeInPast)
+
+	_, err = newSchedulerJob(map[string]string{
+		"type": "rrule",
+	})
+	assert.ErrorIs(t, err, ErrMissingConfigValue)
+}
+
+func TestGetSolarSchedule(t *testing.T) {
+	schedule, err := getSolarSchedule(map[string]string{
+		"latitude":  "51.5074",
+		"longitude": "-0.1278",
+		"event":     "sunset",
+		"offset":    "30m",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &SolarSchedule{latitude: 51.5074, longitude: -0.1278, event: "sunset", offset: 30 * time.Minute}, schedule)
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		err    error
+	}{
+		{
+			name:   "missing latitude",
+			config: map[string]string{"longitude": "0", "event": "sunset"},
+			err:    ErrMissingConfigValue,
+		},
+		{
+			name:   "latitude out of range",
+			config: map[string]string{"latitude": "91", "longitude": "0", "event": "sunset"},
+			err:    ErrInvalidConfigValue,
+		},
+		{
+			name:   "invalid longitude",
+			config: map[string]string{"latitude": "0", "longitude": "west", "event": "sunset"},
+			err:    ErrInvalidConfigValue,
+		},
+		{
+			name:   "missing event",
+			config: map[string]string{"latitude": "0", "longitude": "0"},
+			err:    ErrMissingConfigValue,
+		},
+		{
+			name:   "invalid event",
+			config: map[string]string{"latitude": "0", "longitude": "0", "event": "moonrise"},
+			err:    ErrInvalidConfigValue,
+		},
+		{
+			name:   "invalid offset",
+			config: map[string]string{"latitude": "0", "longitude": "0", "event": "sunset", "offset": "25h"},
+			err:    ErrInvalidConfigValue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := getSolarSchedule(test.config)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+
+	job, err := newSchedulerJob(map[string]string{
+		"type":      "solar",
+		"latitude":  "51.5074",
+		"longitude": "-0.1278",
+		"event":     "sunrise",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, job)
+}